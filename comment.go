@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+
+	descriptor "google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Field numbers used in SourceCodeInfo location paths.
+const (
+	fileSyntaxPath      = 12
+	fileMessageTypePath = 4
+	fileEnumTypePath    = 5
+	fileServicePath     = 6
+	messageFieldPath    = 2
+	messageNestedPath   = 3
+	messageEnumTypePath = 4
+	messageOneofPath    = 8
+	enumValuePath       = 2
+	serviceMethodPath   = 2
+)
+
+type Comments struct {
+	LeadingComments         string
+	TrailingComments        string
+	LeadingDetachedComments []string
+}
+
+type sourceLocations map[string]*descriptor.SourceCodeInfo_Location
+
+func newSourceLocations(info *descriptor.SourceCodeInfo) sourceLocations {
+	locations := make(sourceLocations)
+	for _, location := range info.GetLocation() {
+		locations[fmt.Sprint(location.GetPath())] = location
+	}
+
+	return locations
+}
+
+func (l sourceLocations) comments(path []int32) Comments {
+	location, ok := l[fmt.Sprint(path)]
+	if !ok {
+		return Comments{}
+	}
+
+	return Comments{
+		LeadingComments:         location.GetLeadingComments(),
+		TrailingComments:        location.GetTrailingComments(),
+		LeadingDetachedComments: location.GetLeadingDetachedComments(),
+	}
+}
+
+func appendPath(path []int32, elements ...int32) []int32 {
+	return append(append([]int32{}, path...), elements...)
+}
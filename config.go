@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GenerateConfig is the manifest given by the `config` option to run many targets in one invocation.
+type GenerateConfig struct {
+	Targets []GenerateTarget `yaml:"targets"`
+}
+
+// GenerateTarget holds the options of one target, keyed like the plugin parameter.
+type GenerateTarget struct {
+	Template             string `yaml:"template"`
+	Lang                 string `yaml:"lang"`
+	GenerateType         string `yaml:"generate_type"`
+	OutputPath           string `yaml:"output_path"`
+	AllowMerge           string `yaml:"allow_merge"`
+	Overwrite            string `yaml:"overwrite"`
+	LongType             string `yaml:"long_type"`
+	TypeMap              string `yaml:"type_map"`
+	Strict               string `yaml:"strict"`
+	Header               string `yaml:"header"`
+	EnableMessageFlatten string `yaml:"enable_message_flatten"`
+	// Include and Exclude are glob patterns matched against the message, service, method, enum or file name.
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+}
+
+func NewProtoOptionsFromString(protoOption string) ([]*ProtoOption, error) {
+	params, err := parseParameter(protoOption)
+	if err != nil {
+		return nil, err
+	}
+
+	configPath := params.optional("config")
+	if configPath == "" {
+		option, err := newProtoOption(params)
+		if err != nil {
+			return nil, err
+		}
+		return []*ProtoOption{option}, nil
+	}
+	for key := range params {
+		if key != "config" && key != "dry_run" && key != "out_dir" {
+			return nil, fmt.Errorf("option `%s` cannot be combined with `config`, set it on the targets instead", key)
+		}
+	}
+
+	dryRun, err := params.boolean("dry_run", false)
+	if err != nil {
+		return nil, err
+	}
+	config, err := loadGenerateConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var options []*ProtoOption
+	for i, target := range config.Targets {
+		option, err := target.protoOption()
+		if err != nil {
+			return nil, fmt.Errorf("%s: target %d: %w", configPath, i, err)
+		}
+		// dry_run and out_dir apply to the whole invocation, as protoc writes all files to one directory.
+		option.DryRun = dryRun
+		option.OutDir = params.optional("out_dir")
+		options = append(options, option)
+	}
+
+	return options, nil
+}
+
+func loadGenerateConfig(configPath string) (*GenerateConfig, error) {
+	buf, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unknown keys are rejected, so that a misspelled option is not silently ignored.
+	var config GenerateConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(buf))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", configPath, err)
+	}
+	if len(config.Targets) == 0 {
+		return nil, fmt.Errorf("config %s has no targets", configPath)
+	}
+
+	return &config, nil
+}
+
+// protoOption validates the target like a plugin parameter with the same options.
+func (t GenerateTarget) protoOption() (*ProtoOption, error) {
+	params := protoParameter{}
+	for key, value := range map[string]string{
+		"template":               t.Template,
+		"lang":                   t.Lang,
+		"generate_type":          t.GenerateType,
+		"output_path":            t.OutputPath,
+		"allow_merge":            t.AllowMerge,
+		"overwrite":              t.Overwrite,
+		"long_type":              t.LongType,
+		"type_map":               t.TypeMap,
+		"strict":                 t.Strict,
+		"header":                 t.Header,
+		"enable_message_flatten": t.EnableMessageFlatten,
+	} {
+		if value != "" {
+			params[key] = []string{value}
+		}
+	}
+	if len(t.Include) > 0 {
+		params["include"] = t.Include
+	}
+	if len(t.Exclude) > 0 {
+		params["exclude"] = t.Exclude
+	}
+
+	return newProtoOption(params)
+}
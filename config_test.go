@@ -0,0 +1,137 @@
+package main_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	templatefunc "github.com/deresmos/protoc-gen-template"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProtoOptionsFromString(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    []*templatefunc.ProtoOption
+		wantErr bool
+	}{
+		{
+			name: "Targets with defaults and filters",
+			config: `
+targets:
+  - template: model.template
+    lang: typescript
+    generate_type: message
+    output_path: model.ts
+    include: ["*Request"]
+  - template: service.template
+    lang: dart
+    generate_type: service
+    output_path: service.dart
+    overwrite: false
+`,
+			want: []*templatefunc.ProtoOption{
+				{
+					TemplatePath: "model.template",
+					Language:     "typescript",
+					OutputPath:   "model.ts",
+					GenerateType: "message",
+					Overwrite:    templatefunc.OverwriteAlways,
+					Include:      []string{"*Request"},
+				},
+				{
+					TemplatePath: "service.template",
+					Language:     "dart",
+					OutputPath:   "service.dart",
+					GenerateType: "service",
+					Overwrite:    templatefunc.OverwriteNever,
+				},
+			},
+		},
+		{
+			name: "Missing template",
+			config: `
+targets:
+  - lang: typescript
+    generate_type: message
+    output_path: model.ts
+`,
+			wantErr: true,
+		},
+		{
+			name: "Misspelled option",
+			config: `
+targets:
+  - template: model.template
+    lang: typescript
+    generate_type: message
+    output_path: model.ts
+    overwite: false
+`,
+			wantErr: true,
+		},
+		{
+			name: "Invalid overwrite",
+			config: `
+targets:
+  - template: model.template
+    lang: typescript
+    generate_type: message
+    output_path: model.ts
+    overwrite: sometimes
+`,
+			wantErr: true,
+		},
+		{
+			name: "Invalid strict",
+			config: `
+targets:
+  - template: model.template
+    lang: typescript
+    generate_type: message
+    output_path: model.ts
+    strict: yes
+`,
+			wantErr: true,
+		},
+		{
+			name:    "No targets",
+			config:  "targets: []\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "gen.yaml")
+			err := os.WriteFile(path, []byte(tt.config), 0o644)
+			assert.NoError(t, err)
+
+			got, err := templatefunc.NewProtoOptionsFromString("config=" + path)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, got, len(tt.want))
+			for i, want := range tt.want {
+				assert.Equal(t, want.TemplatePath, got[i].TemplatePath)
+				assert.Equal(t, want.Language, got[i].Language)
+				assert.Equal(t, want.OutputPath, got[i].OutputPath)
+				assert.Equal(t, want.GenerateType, got[i].GenerateType)
+				assert.Equal(t, want.Overwrite, got[i].Overwrite)
+				assert.Equal(t, want.Include, got[i].Include)
+			}
+		})
+	}
+}
+
+func TestNewProtoOptionsFromConfigInvalidDryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gen.yaml")
+	err := os.WriteFile(path, []byte("targets:\n  - template: model.template\n    lang: typescript\n    generate_type: message\n    output_path: model.ts\n"), 0o644)
+	assert.NoError(t, err)
+
+	_, err = templatefunc.NewProtoOptionsFromString("config=" + path + ",dry_run=True")
+	assert.ErrorContains(t, err, "option `dry_run` must be true or false, got `True`")
+}
@@ -0,0 +1,102 @@
+package datatype
+
+import (
+	"fmt"
+	"strings"
+
+	descriptor "google.golang.org/protobuf/types/descriptorpb"
+)
+
+type CSharpDataType struct{}
+
+func (t CSharpDataType) getTypeName(f *descriptor.FieldDescriptorProto) (string, error) {
+	switch f.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_STRING:
+		return "string", nil
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		return "byte[]", nil
+	case descriptor.FieldDescriptorProto_TYPE_INT32,
+		descriptor.FieldDescriptorProto_TYPE_SINT32,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED32:
+		return "int", nil
+	case descriptor.FieldDescriptorProto_TYPE_UINT32,
+		descriptor.FieldDescriptorProto_TYPE_FIXED32:
+		return "uint", nil
+	case descriptor.FieldDescriptorProto_TYPE_INT64,
+		descriptor.FieldDescriptorProto_TYPE_SINT64,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+		return "long", nil
+	case descriptor.FieldDescriptorProto_TYPE_UINT64,
+		descriptor.FieldDescriptorProto_TYPE_FIXED64:
+		return "ulong", nil
+	case descriptor.FieldDescriptorProto_TYPE_FLOAT:
+		return "float", nil
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE:
+		return "double", nil
+	case descriptor.FieldDescriptorProto_TYPE_BOOL:
+		return "bool", nil
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+		if typeName, ok := t.wellKnownTypeName(f.GetTypeName()); ok {
+			return typeName, nil
+		}
+
+		return messageTypeName(f.GetTypeName()), nil
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		return strings.TrimPrefix(f.GetTypeName(), "."), nil
+	}
+
+	return "", fmt.Errorf("unknown type: %s", f.GetType())
+}
+
+func (t CSharpDataType) wellKnownTypeName(typeName string) (string, bool) {
+	switch typeName {
+	case ".google.protobuf.Timestamp":
+		return "DateTimeOffset", true
+	case ".google.protobuf.Duration":
+		return "TimeSpan", true
+	case ".google.protobuf.Struct":
+		return "Dictionary<string, object?>", true
+	case ".google.protobuf.Value":
+		return "object?", true
+	case ".google.protobuf.ListValue":
+		return "List<object?>", true
+	case ".google.protobuf.Any":
+		return "Dictionary<string, object?>", true
+	case ".google.protobuf.FieldMask":
+		return "List<string>", true
+	case ".google.protobuf.Empty":
+		return "object", true
+	case ".google.protobuf.DoubleValue":
+		return "double?", true
+	case ".google.protobuf.FloatValue":
+		return "float?", true
+	case ".google.protobuf.Int64Value":
+		return "long?", true
+	case ".google.protobuf.UInt64Value":
+		return "ulong?", true
+	case ".google.protobuf.Int32Value":
+		return "int?", true
+	case ".google.protobuf.UInt32Value":
+		return "uint?", true
+	case ".google.protobuf.BoolValue":
+		return "bool?", true
+	case ".google.protobuf.StringValue":
+		return "string?", true
+	case ".google.protobuf.BytesValue":
+		return "byte[]?", true
+	}
+
+	return "", false
+}
+
+func (t CSharpDataType) repeatedFormat() string {
+	return "List<%s>"
+}
+
+func (t CSharpDataType) mapFormat() string {
+	return "Dictionary<%s, %s>"
+}
+
+func (t CSharpDataType) optionalFormat() string {
+	return "%s?"
+}
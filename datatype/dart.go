@@ -2,7 +2,6 @@ package datatype
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
 
 	descriptor "google.golang.org/protobuf/types/descriptorpb"
@@ -14,35 +13,88 @@ func (t DartDataType) getTypeName(f *descriptor.FieldDescriptorProto) (string, e
 	switch f.GetType() {
 	case descriptor.FieldDescriptorProto_TYPE_STRING:
 		return "String", nil
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		return "Uint8List", nil
 	case descriptor.FieldDescriptorProto_TYPE_INT32,
 		descriptor.FieldDescriptorProto_TYPE_UINT32,
 		descriptor.FieldDescriptorProto_TYPE_SINT32,
-		descriptor.FieldDescriptorProto_TYPE_INT64,
-		descriptor.FieldDescriptorProto_TYPE_UINT64,
-		descriptor.FieldDescriptorProto_TYPE_SINT64:
+		descriptor.FieldDescriptorProto_TYPE_FIXED32,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED32:
 		return "int", nil
+	case descriptor.FieldDescriptorProto_TYPE_INT64,
+		descriptor.FieldDescriptorProto_TYPE_UINT64,
+		descriptor.FieldDescriptorProto_TYPE_SINT64,
+		descriptor.FieldDescriptorProto_TYPE_FIXED64,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+		return "Int64", nil
 	case descriptor.FieldDescriptorProto_TYPE_DOUBLE,
 		descriptor.FieldDescriptorProto_TYPE_FLOAT:
 		return "double", nil
 	case descriptor.FieldDescriptorProto_TYPE_BOOL:
 		return "bool", nil
 	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
-		switch f.GetTypeName() {
-		case ".google.protobuf.Timestamp":
-			return "DateTime", nil
-		default:
-			if strings.Contains(f.GetTypeName(), "__") {
-				re := regexp.MustCompile(`\.__(\w+)$`)
-				return re.FindStringSubmatch(f.GetTypeName())[1], nil
-			}
-
-			return strings.TrimPrefix(f.GetTypeName(), "."), nil
+		if typeName, ok := t.wellKnownTypeName(f.GetTypeName()); ok {
+			return typeName, nil
 		}
+
+		return messageTypeName(f.GetTypeName()), nil
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		return strings.TrimPrefix(f.GetTypeName(), "."), nil
 	}
 
 	return "", fmt.Errorf("unknown type: %s", f.GetType())
 }
 
+func (t DartDataType) wellKnownTypeName(typeName string) (string, bool) {
+	switch typeName {
+	case ".google.protobuf.Timestamp":
+		return "DateTime", true
+	case ".google.protobuf.Duration":
+		return "Duration", true
+	case ".google.protobuf.Struct":
+		return "Map<String, dynamic>", true
+	case ".google.protobuf.Value":
+		return "dynamic", true
+	case ".google.protobuf.ListValue":
+		return "List<dynamic>", true
+	case ".google.protobuf.Any":
+		return "Map<String, dynamic>", true
+	case ".google.protobuf.FieldMask":
+		return "List<String>", true
+	case ".google.protobuf.Empty":
+		return "Map<String, dynamic>", true
+	case ".google.protobuf.DoubleValue":
+		return "double?", true
+	case ".google.protobuf.FloatValue":
+		return "double?", true
+	case ".google.protobuf.Int64Value":
+		return "Int64?", true
+	case ".google.protobuf.UInt64Value":
+		return "Int64?", true
+	case ".google.protobuf.Int32Value":
+		return "int?", true
+	case ".google.protobuf.UInt32Value":
+		return "int?", true
+	case ".google.protobuf.BoolValue":
+		return "bool?", true
+	case ".google.protobuf.StringValue":
+		return "String?", true
+	case ".google.protobuf.BytesValue":
+		return "Uint8List?", true
+	}
+
+	return "", false
+}
+
 func (t DartDataType) repeatedFormat() string {
 	return "List<%s>"
 }
+
+func (t DartDataType) mapFormat() string {
+	return "Map<%s, %s>"
+}
+
+// optionalFormat leaves proto3 optional fields to templates, which check IsOptional.
+func (t DartDataType) optionalFormat() string {
+	return "%s"
+}
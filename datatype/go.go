@@ -2,7 +2,6 @@ package datatype
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
 
 	descriptor "google.golang.org/protobuf/types/descriptorpb"
@@ -14,13 +13,22 @@ func (t GoDataType) getTypeName(f *descriptor.FieldDescriptorProto) (string, err
 	switch f.GetType() {
 	case descriptor.FieldDescriptorProto_TYPE_STRING:
 		return "string", nil
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		return "[]byte", nil
 	case descriptor.FieldDescriptorProto_TYPE_INT32,
-		descriptor.FieldDescriptorProto_TYPE_UINT32,
 		descriptor.FieldDescriptorProto_TYPE_SINT32,
-		descriptor.FieldDescriptorProto_TYPE_INT64,
-		descriptor.FieldDescriptorProto_TYPE_UINT64,
-		descriptor.FieldDescriptorProto_TYPE_SINT64:
-		return "int", nil
+		descriptor.FieldDescriptorProto_TYPE_SFIXED32:
+		return "int32", nil
+	case descriptor.FieldDescriptorProto_TYPE_UINT32,
+		descriptor.FieldDescriptorProto_TYPE_FIXED32:
+		return "uint32", nil
+	case descriptor.FieldDescriptorProto_TYPE_INT64,
+		descriptor.FieldDescriptorProto_TYPE_SINT64,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+		return "int64", nil
+	case descriptor.FieldDescriptorProto_TYPE_UINT64,
+		descriptor.FieldDescriptorProto_TYPE_FIXED64:
+		return "uint64", nil
 	case descriptor.FieldDescriptorProto_TYPE_FLOAT:
 		return "float32", nil
 	case descriptor.FieldDescriptorProto_TYPE_DOUBLE:
@@ -28,22 +36,68 @@ func (t GoDataType) getTypeName(f *descriptor.FieldDescriptorProto) (string, err
 	case descriptor.FieldDescriptorProto_TYPE_BOOL:
 		return "bool", nil
 	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
-		switch f.GetTypeName() {
-		case ".google.protobuf.Timestamp":
-			return "time.Time", nil
-		default:
-			if strings.Contains(f.GetTypeName(), "__") {
-				re := regexp.MustCompile(`\.__(\w+)$`)
-				return re.FindStringSubmatch(f.GetTypeName())[1], nil
-			}
-
-			return strings.TrimPrefix(f.GetTypeName(), "."), nil
+		if typeName, ok := t.wellKnownTypeName(f.GetTypeName()); ok {
+			return typeName, nil
 		}
+
+		return messageTypeName(f.GetTypeName()), nil
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		return strings.TrimPrefix(f.GetTypeName(), "."), nil
 	}
 
 	return "", fmt.Errorf("unknown type: %s", f.GetType())
 }
 
+func (t GoDataType) wellKnownTypeName(typeName string) (string, bool) {
+	switch typeName {
+	case ".google.protobuf.Timestamp":
+		return "time.Time", true
+	case ".google.protobuf.Duration":
+		return "time.Duration", true
+	case ".google.protobuf.Struct":
+		return "map[string]any", true
+	case ".google.protobuf.Value":
+		return "any", true
+	case ".google.protobuf.ListValue":
+		return "[]any", true
+	case ".google.protobuf.Any":
+		return "map[string]any", true
+	case ".google.protobuf.FieldMask":
+		return "[]string", true
+	case ".google.protobuf.Empty":
+		return "struct{}", true
+	case ".google.protobuf.DoubleValue":
+		return "*float64", true
+	case ".google.protobuf.FloatValue":
+		return "*float32", true
+	case ".google.protobuf.Int64Value":
+		return "*int64", true
+	case ".google.protobuf.UInt64Value":
+		return "*uint64", true
+	case ".google.protobuf.Int32Value":
+		return "*int32", true
+	case ".google.protobuf.UInt32Value":
+		return "*uint32", true
+	case ".google.protobuf.BoolValue":
+		return "*bool", true
+	case ".google.protobuf.StringValue":
+		return "*string", true
+	case ".google.protobuf.BytesValue":
+		return "[]byte", true
+	}
+
+	return "", false
+}
+
 func (t GoDataType) repeatedFormat() string {
 	return "[]%s"
 }
+
+func (t GoDataType) mapFormat() string {
+	return "map[%s]%s"
+}
+
+// optionalFormat leaves proto3 optional fields to templates, which check IsOptional.
+func (t GoDataType) optionalFormat() string {
+	return "%s"
+}
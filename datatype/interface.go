@@ -1,16 +1,61 @@
 package datatype
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 
 	descriptor "google.golang.org/protobuf/types/descriptorpb"
 )
 
+// itemMessagePattern matches item messages, nested messages named with a `__` prefix such as `.Tests.__TestItem`.
+var itemMessagePattern = regexp.MustCompile(`\.__(\w+)$`)
+
+// itemMessageName returns the name of an item message without its parents and prefix, e.g. `TestItem`.
+func itemMessageName(typeName string) (string, bool) {
+	match := itemMessagePattern.FindStringSubmatch(typeName)
+	if match == nil {
+		return "", false
+	}
+
+	return match[1], true
+}
+
+// messageTypeName returns the name of a message type, or of an item message without its parents.
+func messageTypeName(typeName string) string {
+	if itemName, ok := itemMessageName(typeName); ok {
+		return itemName
+	}
+
+	return strings.TrimPrefix(typeName, ".")
+}
+
 type dataType interface {
 	repeatedFormat() string
+	mapFormat() string
+	optionalFormat() string
 	getTypeName(f *descriptor.FieldDescriptorProto) (string, error)
 }
 
+// boxer is implemented by languages whose primitives cannot be used in generics or be null, e.g. Java.
+type boxer interface {
+	boxedTypeName(typeName string) string
+}
+
+func (d DataType) boxed(typeName string) string {
+	if b, ok := d.dataType.(boxer); ok {
+		return b.boxedTypeName(typeName)
+	}
+
+	return typeName
+}
+
+// commenter is implemented by languages whose line comments do not start with `//`, e.g. Python.
+type commenter interface {
+	commentPrefix() string
+}
+
 type DataType struct {
 	dataType dataType
 }
@@ -25,25 +70,106 @@ func (d DataType) GetName(f *descriptor.FieldDescriptorProto) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if f.GetProto3Optional() {
+		typeName = formatOptional(d.dataType.optionalFormat(), d.boxed(typeName))
+	}
+	if format != "%s" {
+		typeName = d.boxed(typeName)
+	}
+	// Union types such as `string | null` need parentheses inside a repeated format.
+	if format != "%s" && strings.Contains(typeName, " | ") {
+		typeName = "(" + typeName + ")"
+	}
 
 	return fmt.Sprintf(format, typeName), nil
 }
 
-func factoryDataType(lang string) (dataType, error) {
+// formatOptional applies format unless typeName is already nullable, e.g. a wrapper type `String?`.
+func formatOptional(format, typeName string) string {
+	prefix, suffix, _ := strings.Cut(format, "%s")
+	if strings.HasPrefix(typeName, prefix) && strings.HasSuffix(typeName, suffix) {
+		return typeName
+	}
+
+	return fmt.Sprintf(format, typeName)
+}
+
+func (d DataType) GetMapName(key, value *descriptor.FieldDescriptorProto) (string, error) {
+	keyTypeName, err := d.dataType.getTypeName(key)
+	if err != nil {
+		return "", err
+	}
+	valueTypeName, err := d.dataType.getTypeName(value)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(d.dataType.mapFormat(), d.boxed(keyTypeName), d.boxed(valueTypeName)), nil
+}
+
+// CommentPrefix returns the line comment prefix of the language, or "" when a type map defining
+// a new language does not set comment_prefix.
+func (d DataType) CommentPrefix() string {
+	if c, ok := d.dataType.(commenter); ok {
+		return c.commentPrefix()
+	}
+
+	return "//"
+}
+
+type Option struct {
+	// LongType selects the TypeScript type of 64-bit integers. Default "number".
+	LongType string
+	// TypeMapPath is a YAML or JSON TypeMap overriding lang, or defining it if lang is not built in.
+	TypeMapPath string
+}
+
+var errUnknownLanguage = errors.New("unknown language")
+
+func factoryDataType(lang string, option Option) (dataType, error) {
 	switch lang {
 	case "typescript":
-		return &TypeScriptDataType{}, nil
+		longType := option.LongType
+		if longType == "" {
+			longType = "number"
+		}
+		switch longType {
+		case "number", "bigint", "string":
+		default:
+			return nil, fmt.Errorf("unknown long type: %s", longType)
+		}
+		return &TypeScriptDataType{longType: longType}, nil
 	case "dart":
 		return &DartDataType{}, nil
 	case "go":
 		return &GoDataType{}, nil
+	case "kotlin":
+		return &KotlinDataType{}, nil
+	case "swift":
+		return &SwiftDataType{}, nil
+	case "python":
+		return &PythonDataType{}, nil
+	case "rust":
+		return &RustDataType{}, nil
+	case "java":
+		return &JavaDataType{}, nil
+	case "csharp":
+		return &CSharpDataType{}, nil
 	}
 
-	return nil, fmt.Errorf("unknown language: %s", lang)
+	return nil, fmt.Errorf("%w: %s", errUnknownLanguage, lang)
 }
 
-func NewDataType(lang string) (*DataType, error) {
-	dataType, err := factoryDataType(lang)
+func NewDataType(lang string, option Option) (*DataType, error) {
+	dataType, err := factoryDataType(lang, option)
+	if option.TypeMapPath != "" && (err == nil || errors.Is(err, errUnknownLanguage)) {
+		var typeMap *TypeMap
+		typeMap, err = LoadTypeMap(option.TypeMapPath)
+		if err != nil {
+			return nil, err
+		}
+		dataType, err = newCustomDataType(typeMap, dataType)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,496 @@
+package datatype_test
+
+import (
+	"testing"
+
+	"github.com/deresmos/protoc-gen-template/datatype"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	descriptor "google.golang.org/protobuf/types/descriptorpb"
+)
+
+func newField(fieldType descriptor.FieldDescriptorProto_Type, label descriptor.FieldDescriptorProto_Label) *descriptor.FieldDescriptorProto {
+	return &descriptor.FieldDescriptorProto{
+		Type:  fieldType.Enum(),
+		Label: label.Enum(),
+	}
+}
+
+func newProto3OptionalField(fieldType descriptor.FieldDescriptorProto_Type) *descriptor.FieldDescriptorProto {
+	field := newField(fieldType, descriptor.FieldDescriptorProto_LABEL_OPTIONAL)
+	field.Proto3Optional = proto.Bool(true)
+	return field
+}
+
+func TestGetNameScalar(t *testing.T) {
+	optional := descriptor.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated := descriptor.FieldDescriptorProto_LABEL_REPEATED
+
+	tests := []struct {
+		name   string
+		lang   string
+		option datatype.Option
+		field  *descriptor.FieldDescriptorProto
+		want   string
+	}{
+		{
+			name:  "typescript: double -> number",
+			lang:  "typescript",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_DOUBLE, optional),
+			want:  "number",
+		},
+		{
+			name:  "typescript: int64 -> number",
+			lang:  "typescript",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_INT64, optional),
+			want:  "number",
+		},
+		{
+			name:   "typescript: fixed64 -> bigint with long_type=bigint",
+			lang:   "typescript",
+			option: datatype.Option{LongType: "bigint"},
+			field:  newField(descriptor.FieldDescriptorProto_TYPE_FIXED64, optional),
+			want:   "bigint",
+		},
+		{
+			name:  "typescript: repeated bytes -> Uint8Array[]",
+			lang:  "typescript",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_BYTES, repeated),
+			want:  "Uint8Array[]",
+		},
+		{
+			name:  "dart: sint64 -> Int64",
+			lang:  "dart",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_SINT64, optional),
+			want:  "Int64",
+		},
+		{
+			name:  "dart: bytes -> Uint8List",
+			lang:  "dart",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_BYTES, optional),
+			want:  "Uint8List",
+		},
+		{
+			name:  "go: sfixed32 -> int32",
+			lang:  "go",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_SFIXED32, optional),
+			want:  "int32",
+		},
+		{
+			name:  "go: fixed64 -> uint64",
+			lang:  "go",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_FIXED64, optional),
+			want:  "uint64",
+		},
+		{
+			name:  "go: repeated bytes -> [][]byte",
+			lang:  "go",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_BYTES, repeated),
+			want:  "[][]byte",
+		},
+		{
+			name:  "kotlin: uint64 -> ULong",
+			lang:  "kotlin",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_UINT64, optional),
+			want:  "ULong",
+		},
+		{
+			name:  "kotlin: repeated string -> List<String>",
+			lang:  "kotlin",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_STRING, repeated),
+			want:  "List<String>",
+		},
+		{
+			name:  "kotlin: optional int32 -> Int?",
+			lang:  "kotlin",
+			field: newProto3OptionalField(descriptor.FieldDescriptorProto_TYPE_INT32),
+			want:  "Int?",
+		},
+		{
+			name:  "swift: uint64 -> UInt64",
+			lang:  "swift",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_UINT64, optional),
+			want:  "UInt64",
+		},
+		{
+			name:  "swift: repeated string -> [String]",
+			lang:  "swift",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_STRING, repeated),
+			want:  "[String]",
+		},
+		{
+			name:  "swift: optional int32 -> Int32?",
+			lang:  "swift",
+			field: newProto3OptionalField(descriptor.FieldDescriptorProto_TYPE_INT32),
+			want:  "Int32?",
+		},
+		{
+			name:  "python: uint64 -> int",
+			lang:  "python",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_UINT64, optional),
+			want:  "int",
+		},
+		{
+			name:  "python: repeated string -> list[str]",
+			lang:  "python",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_STRING, repeated),
+			want:  "list[str]",
+		},
+		{
+			name:  "python: optional int32 -> Optional[int]",
+			lang:  "python",
+			field: newProto3OptionalField(descriptor.FieldDescriptorProto_TYPE_INT32),
+			want:  "Optional[int]",
+		},
+		{
+			name:  "rust: uint64 -> u64",
+			lang:  "rust",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_UINT64, optional),
+			want:  "u64",
+		},
+		{
+			name:  "rust: repeated string -> Vec<String>",
+			lang:  "rust",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_STRING, repeated),
+			want:  "Vec<String>",
+		},
+		{
+			name:  "rust: optional int32 -> Option<i32>",
+			lang:  "rust",
+			field: newProto3OptionalField(descriptor.FieldDescriptorProto_TYPE_INT32),
+			want:  "Option<i32>",
+		},
+		{
+			name:  "java: uint64 -> long",
+			lang:  "java",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_UINT64, optional),
+			want:  "long",
+		},
+		{
+			name:  "java: repeated string -> List<String>",
+			lang:  "java",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_STRING, repeated),
+			want:  "List<String>",
+		},
+		{
+			name:  "java: optional int32 -> Integer",
+			lang:  "java",
+			field: newProto3OptionalField(descriptor.FieldDescriptorProto_TYPE_INT32),
+			want:  "Integer",
+		},
+		{
+			name:  "java: repeated int64 -> List<Long>",
+			lang:  "java",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_INT64, repeated),
+			want:  "List<Long>",
+		},
+		{
+			name:  "csharp: uint64 -> ulong",
+			lang:  "csharp",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_UINT64, optional),
+			want:  "ulong",
+		},
+		{
+			name:  "csharp: repeated string -> List<string>",
+			lang:  "csharp",
+			field: newField(descriptor.FieldDescriptorProto_TYPE_STRING, repeated),
+			want:  "List<string>",
+		},
+		{
+			name:  "csharp: optional int32 -> int?",
+			lang:  "csharp",
+			field: newProto3OptionalField(descriptor.FieldDescriptorProto_TYPE_INT32),
+			want:  "int?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataType, err := datatype.NewDataType(tt.lang, tt.option)
+			assert.NoError(t, err)
+
+			got, err := dataType.GetName(tt.field)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetNameMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		lang     string
+		typeName string
+		want     string
+	}{
+		{
+			name:     "typescript: nested message",
+			lang:     "typescript",
+			typeName: ".Tests.NestTests",
+			want:     "Tests.NestTests",
+		},
+		{
+			name:     "rust: nested message -> module path",
+			lang:     "rust",
+			typeName: ".pkg.v1.OuterMessage.Inner",
+			want:     "pkg::v1::outer_message::Inner",
+		},
+		{
+			name:     "typescript: item message",
+			lang:     "typescript",
+			typeName: ".Tests.__TestItem",
+			want:     "TestItem",
+		},
+		{
+			name:     "kotlin: double underscore in a message name",
+			lang:     "kotlin",
+			typeName: ".pkg.Foo__Bar",
+			want:     "pkg.Foo__Bar",
+		},
+		{
+			name:     "rust: item message",
+			lang:     "rust",
+			typeName: ".pkg.Tests.__TestItem",
+			want:     "TestItem",
+		},
+		{
+			name:     "rust: double underscore in a message name",
+			lang:     "rust",
+			typeName: ".pkg.Foo__Bar",
+			want:     "pkg::Foo__Bar",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := newField(descriptor.FieldDescriptorProto_TYPE_MESSAGE, descriptor.FieldDescriptorProto_LABEL_OPTIONAL)
+			field.TypeName = proto.String(tt.typeName)
+
+			dataType, err := datatype.NewDataType(tt.lang, datatype.Option{})
+			assert.NoError(t, err)
+
+			got, err := dataType.GetName(field)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetNameWellKnownType(t *testing.T) {
+	tests := []struct {
+		name           string
+		lang           string
+		typeName       string
+		label          descriptor.FieldDescriptorProto_Label
+		proto3Optional bool
+		want           string
+	}{
+		{
+			name:     "typescript: Duration -> string",
+			lang:     "typescript",
+			typeName: ".google.protobuf.Duration",
+			want:     "string",
+		},
+		{
+			name:     "typescript: repeated StringValue -> (string | null)[]",
+			lang:     "typescript",
+			typeName: ".google.protobuf.StringValue",
+			label:    descriptor.FieldDescriptorProto_LABEL_REPEATED,
+			want:     "(string | null)[]",
+		},
+		{
+			name:     "dart: Struct -> Map<String, dynamic>",
+			lang:     "dart",
+			typeName: ".google.protobuf.Struct",
+			want:     "Map<String, dynamic>",
+		},
+		{
+			name:     "dart: Int32Value -> int?",
+			lang:     "dart",
+			typeName: ".google.protobuf.Int32Value",
+			want:     "int?",
+		},
+		{
+			name:     "go: Duration -> time.Duration",
+			lang:     "go",
+			typeName: ".google.protobuf.Duration",
+			want:     "time.Duration",
+		},
+		{
+			name:     "go: BoolValue -> *bool",
+			lang:     "go",
+			typeName: ".google.protobuf.BoolValue",
+			want:     "*bool",
+		},
+		{
+			name:           "kotlin: optional StringValue -> String?",
+			lang:           "kotlin",
+			typeName:       ".google.protobuf.StringValue",
+			proto3Optional: true,
+			want:           "String?",
+		},
+		{
+			name:     "kotlin: Timestamp -> Instant",
+			lang:     "kotlin",
+			typeName: ".google.protobuf.Timestamp",
+			want:     "Instant",
+		},
+		{
+			name:           "swift: optional StringValue -> String?",
+			lang:           "swift",
+			typeName:       ".google.protobuf.StringValue",
+			proto3Optional: true,
+			want:           "String?",
+		},
+		{
+			name:     "swift: Timestamp -> Date",
+			lang:     "swift",
+			typeName: ".google.protobuf.Timestamp",
+			want:     "Date",
+		},
+		{
+			name:           "python: optional StringValue -> Optional[str]",
+			lang:           "python",
+			typeName:       ".google.protobuf.StringValue",
+			proto3Optional: true,
+			want:           "Optional[str]",
+		},
+		{
+			name:     "python: Timestamp -> datetime.datetime",
+			lang:     "python",
+			typeName: ".google.protobuf.Timestamp",
+			want:     "datetime.datetime",
+		},
+		{
+			name:           "rust: optional StringValue -> Option<String>",
+			lang:           "rust",
+			typeName:       ".google.protobuf.StringValue",
+			proto3Optional: true,
+			want:           "Option<String>",
+		},
+		{
+			name:     "rust: Timestamp -> chrono::DateTime<chrono::Utc>",
+			lang:     "rust",
+			typeName: ".google.protobuf.Timestamp",
+			want:     "chrono::DateTime<chrono::Utc>",
+		},
+		{
+			name:           "java: optional StringValue -> String",
+			lang:           "java",
+			typeName:       ".google.protobuf.StringValue",
+			proto3Optional: true,
+			want:           "String",
+		},
+		{
+			name:     "java: Timestamp -> Instant",
+			lang:     "java",
+			typeName: ".google.protobuf.Timestamp",
+			want:     "Instant",
+		},
+		{
+			name:           "csharp: optional StringValue -> string?",
+			lang:           "csharp",
+			typeName:       ".google.protobuf.StringValue",
+			proto3Optional: true,
+			want:           "string?",
+		},
+		{
+			name:     "csharp: Timestamp -> DateTimeOffset",
+			lang:     "csharp",
+			typeName: ".google.protobuf.Timestamp",
+			want:     "DateTimeOffset",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			label := tt.label
+			if label == 0 {
+				label = descriptor.FieldDescriptorProto_LABEL_OPTIONAL
+			}
+			field := newField(descriptor.FieldDescriptorProto_TYPE_MESSAGE, label)
+			field.TypeName = proto.String(tt.typeName)
+			if tt.proto3Optional {
+				field.Proto3Optional = proto.Bool(true)
+			}
+
+			dataType, err := datatype.NewDataType(tt.lang, datatype.Option{})
+			assert.NoError(t, err)
+
+			got, err := dataType.GetName(field)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetMapName(t *testing.T) {
+	tests := []struct {
+		name  string
+		lang  string
+		key   descriptor.FieldDescriptorProto_Type
+		value descriptor.FieldDescriptorProto_Type
+		want  string
+	}{
+		{
+			name:  "kotlin: map<string, int64> -> Map<String, Long>",
+			lang:  "kotlin",
+			key:   descriptor.FieldDescriptorProto_TYPE_STRING,
+			value: descriptor.FieldDescriptorProto_TYPE_INT64,
+			want:  "Map<String, Long>",
+		},
+		{
+			name:  "swift: map<string, int64> -> [String: Int64]",
+			lang:  "swift",
+			key:   descriptor.FieldDescriptorProto_TYPE_STRING,
+			value: descriptor.FieldDescriptorProto_TYPE_INT64,
+			want:  "[String: Int64]",
+		},
+		{
+			name:  "python: map<string, int64> -> dict[str, int]",
+			lang:  "python",
+			key:   descriptor.FieldDescriptorProto_TYPE_STRING,
+			value: descriptor.FieldDescriptorProto_TYPE_INT64,
+			want:  "dict[str, int]",
+		},
+		{
+			name:  "rust: map<string, int64> -> HashMap<String, i64>",
+			lang:  "rust",
+			key:   descriptor.FieldDescriptorProto_TYPE_STRING,
+			value: descriptor.FieldDescriptorProto_TYPE_INT64,
+			want:  "HashMap<String, i64>",
+		},
+		{
+			name:  "java: map<string, int64> -> Map<String, Long>",
+			lang:  "java",
+			key:   descriptor.FieldDescriptorProto_TYPE_STRING,
+			value: descriptor.FieldDescriptorProto_TYPE_INT64,
+			want:  "Map<String, Long>",
+		},
+		{
+			name:  "csharp: map<string, int64> -> Dictionary<string, long>",
+			lang:  "csharp",
+			key:   descriptor.FieldDescriptorProto_TYPE_STRING,
+			value: descriptor.FieldDescriptorProto_TYPE_INT64,
+			want:  "Dictionary<string, long>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataType, err := datatype.NewDataType(tt.lang, datatype.Option{})
+			assert.NoError(t, err)
+
+			key := newField(tt.key, descriptor.FieldDescriptorProto_LABEL_OPTIONAL)
+			value := newField(tt.value, descriptor.FieldDescriptorProto_LABEL_OPTIONAL)
+			got, err := dataType.GetMapName(key, value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewDataTypeUnknownLongType(t *testing.T) {
+	_, err := datatype.NewDataType("typescript", datatype.Option{LongType: "float"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,119 @@
+package datatype
+
+import (
+	"fmt"
+	"strings"
+
+	descriptor "google.golang.org/protobuf/types/descriptorpb"
+)
+
+type JavaDataType struct{}
+
+func (t JavaDataType) getTypeName(f *descriptor.FieldDescriptorProto) (string, error) {
+	switch f.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_STRING:
+		return "String", nil
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		return "byte[]", nil
+	case descriptor.FieldDescriptorProto_TYPE_INT32,
+		descriptor.FieldDescriptorProto_TYPE_SINT32,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED32,
+		descriptor.FieldDescriptorProto_TYPE_UINT32,
+		descriptor.FieldDescriptorProto_TYPE_FIXED32:
+		return "int", nil
+	case descriptor.FieldDescriptorProto_TYPE_INT64,
+		descriptor.FieldDescriptorProto_TYPE_SINT64,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED64,
+		descriptor.FieldDescriptorProto_TYPE_UINT64,
+		descriptor.FieldDescriptorProto_TYPE_FIXED64:
+		return "long", nil
+	case descriptor.FieldDescriptorProto_TYPE_FLOAT:
+		return "float", nil
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE:
+		return "double", nil
+	case descriptor.FieldDescriptorProto_TYPE_BOOL:
+		return "boolean", nil
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+		if typeName, ok := t.wellKnownTypeName(f.GetTypeName()); ok {
+			return typeName, nil
+		}
+
+		return messageTypeName(f.GetTypeName()), nil
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		return strings.TrimPrefix(f.GetTypeName(), "."), nil
+	}
+
+	return "", fmt.Errorf("unknown type: %s", f.GetType())
+}
+
+func (t JavaDataType) wellKnownTypeName(typeName string) (string, bool) {
+	switch typeName {
+	case ".google.protobuf.Timestamp":
+		return "Instant", true
+	case ".google.protobuf.Duration":
+		return "Duration", true
+	case ".google.protobuf.Struct":
+		return "Map<String, Object>", true
+	case ".google.protobuf.Value":
+		return "Object", true
+	case ".google.protobuf.ListValue":
+		return "List<Object>", true
+	case ".google.protobuf.Any":
+		return "Map<String, Object>", true
+	case ".google.protobuf.FieldMask":
+		return "List<String>", true
+	case ".google.protobuf.Empty":
+		return "Void", true
+	case ".google.protobuf.DoubleValue":
+		return "Double", true
+	case ".google.protobuf.FloatValue":
+		return "Float", true
+	case ".google.protobuf.Int64Value":
+		return "Long", true
+	case ".google.protobuf.UInt64Value":
+		return "Long", true
+	case ".google.protobuf.Int32Value":
+		return "Integer", true
+	case ".google.protobuf.UInt32Value":
+		return "Integer", true
+	case ".google.protobuf.BoolValue":
+		return "Boolean", true
+	case ".google.protobuf.StringValue":
+		return "String", true
+	case ".google.protobuf.BytesValue":
+		return "byte[]", true
+	}
+
+	return "", false
+}
+
+func (t JavaDataType) repeatedFormat() string {
+	return "List<%s>"
+}
+
+func (t JavaDataType) mapFormat() string {
+	return "Map<%s, %s>"
+}
+
+// optionalFormat relies on boxedTypeName, since boxed types are already nullable.
+func (t JavaDataType) optionalFormat() string {
+	return "%s"
+}
+
+// boxedTypeName returns the reference type used inside generics and for nullable values.
+func (t JavaDataType) boxedTypeName(typeName string) string {
+	switch typeName {
+	case "int":
+		return "Integer"
+	case "long":
+		return "Long"
+	case "float":
+		return "Float"
+	case "double":
+		return "Double"
+	case "boolean":
+		return "Boolean"
+	}
+
+	return typeName
+}
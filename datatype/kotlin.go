@@ -0,0 +1,102 @@
+package datatype
+
+import (
+	"fmt"
+	"strings"
+
+	descriptor "google.golang.org/protobuf/types/descriptorpb"
+)
+
+type KotlinDataType struct{}
+
+func (t KotlinDataType) getTypeName(f *descriptor.FieldDescriptorProto) (string, error) {
+	switch f.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_STRING:
+		return "String", nil
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		return "ByteArray", nil
+	case descriptor.FieldDescriptorProto_TYPE_INT32,
+		descriptor.FieldDescriptorProto_TYPE_SINT32,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED32:
+		return "Int", nil
+	case descriptor.FieldDescriptorProto_TYPE_UINT32,
+		descriptor.FieldDescriptorProto_TYPE_FIXED32:
+		return "UInt", nil
+	case descriptor.FieldDescriptorProto_TYPE_INT64,
+		descriptor.FieldDescriptorProto_TYPE_SINT64,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+		return "Long", nil
+	case descriptor.FieldDescriptorProto_TYPE_UINT64,
+		descriptor.FieldDescriptorProto_TYPE_FIXED64:
+		return "ULong", nil
+	case descriptor.FieldDescriptorProto_TYPE_FLOAT:
+		return "Float", nil
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE:
+		return "Double", nil
+	case descriptor.FieldDescriptorProto_TYPE_BOOL:
+		return "Boolean", nil
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+		if typeName, ok := t.wellKnownTypeName(f.GetTypeName()); ok {
+			return typeName, nil
+		}
+
+		return messageTypeName(f.GetTypeName()), nil
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		return strings.TrimPrefix(f.GetTypeName(), "."), nil
+	}
+
+	return "", fmt.Errorf("unknown type: %s", f.GetType())
+}
+
+func (t KotlinDataType) wellKnownTypeName(typeName string) (string, bool) {
+	switch typeName {
+	case ".google.protobuf.Timestamp":
+		return "Instant", true
+	case ".google.protobuf.Duration":
+		return "Duration", true
+	case ".google.protobuf.Struct":
+		return "Map<String, Any?>", true
+	case ".google.protobuf.Value":
+		return "Any?", true
+	case ".google.protobuf.ListValue":
+		return "List<Any?>", true
+	case ".google.protobuf.Any":
+		return "Map<String, Any?>", true
+	case ".google.protobuf.FieldMask":
+		return "List<String>", true
+	case ".google.protobuf.Empty":
+		return "Unit", true
+	case ".google.protobuf.DoubleValue":
+		return "Double?", true
+	case ".google.protobuf.FloatValue":
+		return "Float?", true
+	case ".google.protobuf.Int64Value":
+		return "Long?", true
+	case ".google.protobuf.UInt64Value":
+		return "ULong?", true
+	case ".google.protobuf.Int32Value":
+		return "Int?", true
+	case ".google.protobuf.UInt32Value":
+		return "UInt?", true
+	case ".google.protobuf.BoolValue":
+		return "Boolean?", true
+	case ".google.protobuf.StringValue":
+		return "String?", true
+	case ".google.protobuf.BytesValue":
+		return "ByteArray?", true
+	}
+
+	return "", false
+}
+
+func (t KotlinDataType) repeatedFormat() string {
+	return "List<%s>"
+}
+
+func (t KotlinDataType) mapFormat() string {
+	return "Map<%s, %s>"
+}
+
+func (t KotlinDataType) optionalFormat() string {
+	return "%s?"
+}
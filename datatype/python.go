@@ -0,0 +1,102 @@
+package datatype
+
+import (
+	"fmt"
+	"strings"
+
+	descriptor "google.golang.org/protobuf/types/descriptorpb"
+)
+
+type PythonDataType struct{}
+
+func (t PythonDataType) getTypeName(f *descriptor.FieldDescriptorProto) (string, error) {
+	switch f.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_STRING:
+		return "str", nil
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		return "bytes", nil
+	case descriptor.FieldDescriptorProto_TYPE_INT32,
+		descriptor.FieldDescriptorProto_TYPE_UINT32,
+		descriptor.FieldDescriptorProto_TYPE_SINT32,
+		descriptor.FieldDescriptorProto_TYPE_FIXED32,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED32,
+		descriptor.FieldDescriptorProto_TYPE_INT64,
+		descriptor.FieldDescriptorProto_TYPE_UINT64,
+		descriptor.FieldDescriptorProto_TYPE_SINT64,
+		descriptor.FieldDescriptorProto_TYPE_FIXED64,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+		return "int", nil
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE,
+		descriptor.FieldDescriptorProto_TYPE_FLOAT:
+		return "float", nil
+	case descriptor.FieldDescriptorProto_TYPE_BOOL:
+		return "bool", nil
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+		if typeName, ok := t.wellKnownTypeName(f.GetTypeName()); ok {
+			return typeName, nil
+		}
+
+		return messageTypeName(f.GetTypeName()), nil
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		return strings.TrimPrefix(f.GetTypeName(), "."), nil
+	}
+
+	return "", fmt.Errorf("unknown type: %s", f.GetType())
+}
+
+func (t PythonDataType) wellKnownTypeName(typeName string) (string, bool) {
+	switch typeName {
+	case ".google.protobuf.Timestamp":
+		return "datetime.datetime", true
+	case ".google.protobuf.Duration":
+		return "datetime.timedelta", true
+	case ".google.protobuf.Struct":
+		return "dict[str, Any]", true
+	case ".google.protobuf.Value":
+		return "Any", true
+	case ".google.protobuf.ListValue":
+		return "list[Any]", true
+	case ".google.protobuf.Any":
+		return "dict[str, Any]", true
+	case ".google.protobuf.FieldMask":
+		return "list[str]", true
+	case ".google.protobuf.Empty":
+		return "None", true
+	case ".google.protobuf.DoubleValue":
+		return "Optional[float]", true
+	case ".google.protobuf.FloatValue":
+		return "Optional[float]", true
+	case ".google.protobuf.Int64Value":
+		return "Optional[int]", true
+	case ".google.protobuf.UInt64Value":
+		return "Optional[int]", true
+	case ".google.protobuf.Int32Value":
+		return "Optional[int]", true
+	case ".google.protobuf.UInt32Value":
+		return "Optional[int]", true
+	case ".google.protobuf.BoolValue":
+		return "Optional[bool]", true
+	case ".google.protobuf.StringValue":
+		return "Optional[str]", true
+	case ".google.protobuf.BytesValue":
+		return "Optional[bytes]", true
+	}
+
+	return "", false
+}
+
+func (t PythonDataType) repeatedFormat() string {
+	return "list[%s]"
+}
+
+func (t PythonDataType) mapFormat() string {
+	return "dict[%s, %s]"
+}
+
+func (t PythonDataType) optionalFormat() string {
+	return "Optional[%s]"
+}
+
+func (t PythonDataType) commentPrefix() string {
+	return "#"
+}
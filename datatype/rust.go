@@ -0,0 +1,120 @@
+package datatype
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/iancoleman/strcase"
+	descriptor "google.golang.org/protobuf/types/descriptorpb"
+)
+
+type RustDataType struct{}
+
+func (t RustDataType) getTypeName(f *descriptor.FieldDescriptorProto) (string, error) {
+	switch f.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_STRING:
+		return "String", nil
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		return "Vec<u8>", nil
+	case descriptor.FieldDescriptorProto_TYPE_INT32,
+		descriptor.FieldDescriptorProto_TYPE_SINT32,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED32:
+		return "i32", nil
+	case descriptor.FieldDescriptorProto_TYPE_UINT32,
+		descriptor.FieldDescriptorProto_TYPE_FIXED32:
+		return "u32", nil
+	case descriptor.FieldDescriptorProto_TYPE_INT64,
+		descriptor.FieldDescriptorProto_TYPE_SINT64,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+		return "i64", nil
+	case descriptor.FieldDescriptorProto_TYPE_UINT64,
+		descriptor.FieldDescriptorProto_TYPE_FIXED64:
+		return "u64", nil
+	case descriptor.FieldDescriptorProto_TYPE_FLOAT:
+		return "f32", nil
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE:
+		return "f64", nil
+	case descriptor.FieldDescriptorProto_TYPE_BOOL:
+		return "bool", nil
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+		if typeName, ok := t.wellKnownTypeName(f.GetTypeName()); ok {
+			return typeName, nil
+		}
+
+		if itemName, ok := itemMessageName(f.GetTypeName()); ok {
+			return itemName, nil
+		}
+
+		return t.pathName(f.GetTypeName()), nil
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		return t.pathName(f.GetTypeName()), nil
+	}
+
+	return "", fmt.Errorf("unknown type: %s", f.GetType())
+}
+
+// pathName converts `.pkg.Outer.Inner` into the module path `pkg::outer::Inner`.
+func (t RustDataType) pathName(typeName string) string {
+	segments := strings.Split(strings.TrimPrefix(typeName, "."), ".")
+	for i, segment := range segments[:len(segments)-1] {
+		// Package segments are already module names; only outer messages are converted.
+		if unicode.IsUpper(rune(segment[0])) {
+			segments[i] = strcase.ToSnake(segment)
+		}
+	}
+	return strings.Join(segments, "::")
+}
+
+func (t RustDataType) wellKnownTypeName(typeName string) (string, bool) {
+	switch typeName {
+	case ".google.protobuf.Timestamp":
+		return "chrono::DateTime<chrono::Utc>", true
+	case ".google.protobuf.Duration":
+		return "std::time::Duration", true
+	case ".google.protobuf.Struct":
+		return "serde_json::Map<String, serde_json::Value>", true
+	case ".google.protobuf.Value":
+		return "serde_json::Value", true
+	case ".google.protobuf.ListValue":
+		return "Vec<serde_json::Value>", true
+	case ".google.protobuf.Any":
+		return "serde_json::Map<String, serde_json::Value>", true
+	case ".google.protobuf.FieldMask":
+		return "Vec<String>", true
+	case ".google.protobuf.Empty":
+		return "()", true
+	case ".google.protobuf.DoubleValue":
+		return "Option<f64>", true
+	case ".google.protobuf.FloatValue":
+		return "Option<f32>", true
+	case ".google.protobuf.Int64Value":
+		return "Option<i64>", true
+	case ".google.protobuf.UInt64Value":
+		return "Option<u64>", true
+	case ".google.protobuf.Int32Value":
+		return "Option<i32>", true
+	case ".google.protobuf.UInt32Value":
+		return "Option<u32>", true
+	case ".google.protobuf.BoolValue":
+		return "Option<bool>", true
+	case ".google.protobuf.StringValue":
+		return "Option<String>", true
+	case ".google.protobuf.BytesValue":
+		return "Option<Vec<u8>>", true
+	}
+
+	return "", false
+}
+
+func (t RustDataType) repeatedFormat() string {
+	return "Vec<%s>"
+}
+
+func (t RustDataType) mapFormat() string {
+	return "HashMap<%s, %s>"
+}
+
+func (t RustDataType) optionalFormat() string {
+	return "Option<%s>"
+}
@@ -0,0 +1,102 @@
+package datatype
+
+import (
+	"fmt"
+	"strings"
+
+	descriptor "google.golang.org/protobuf/types/descriptorpb"
+)
+
+type SwiftDataType struct{}
+
+func (t SwiftDataType) getTypeName(f *descriptor.FieldDescriptorProto) (string, error) {
+	switch f.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_STRING:
+		return "String", nil
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		return "Data", nil
+	case descriptor.FieldDescriptorProto_TYPE_INT32,
+		descriptor.FieldDescriptorProto_TYPE_SINT32,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED32:
+		return "Int32", nil
+	case descriptor.FieldDescriptorProto_TYPE_UINT32,
+		descriptor.FieldDescriptorProto_TYPE_FIXED32:
+		return "UInt32", nil
+	case descriptor.FieldDescriptorProto_TYPE_INT64,
+		descriptor.FieldDescriptorProto_TYPE_SINT64,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+		return "Int64", nil
+	case descriptor.FieldDescriptorProto_TYPE_UINT64,
+		descriptor.FieldDescriptorProto_TYPE_FIXED64:
+		return "UInt64", nil
+	case descriptor.FieldDescriptorProto_TYPE_FLOAT:
+		return "Float", nil
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE:
+		return "Double", nil
+	case descriptor.FieldDescriptorProto_TYPE_BOOL:
+		return "Bool", nil
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+		if typeName, ok := t.wellKnownTypeName(f.GetTypeName()); ok {
+			return typeName, nil
+		}
+
+		return messageTypeName(f.GetTypeName()), nil
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		return strings.TrimPrefix(f.GetTypeName(), "."), nil
+	}
+
+	return "", fmt.Errorf("unknown type: %s", f.GetType())
+}
+
+func (t SwiftDataType) wellKnownTypeName(typeName string) (string, bool) {
+	switch typeName {
+	case ".google.protobuf.Timestamp":
+		return "Date", true
+	case ".google.protobuf.Duration":
+		return "TimeInterval", true
+	case ".google.protobuf.Struct":
+		return "[String: Any]", true
+	case ".google.protobuf.Value":
+		return "Any", true
+	case ".google.protobuf.ListValue":
+		return "[Any]", true
+	case ".google.protobuf.Any":
+		return "[String: Any]", true
+	case ".google.protobuf.FieldMask":
+		return "[String]", true
+	case ".google.protobuf.Empty":
+		return "Void", true
+	case ".google.protobuf.DoubleValue":
+		return "Double?", true
+	case ".google.protobuf.FloatValue":
+		return "Float?", true
+	case ".google.protobuf.Int64Value":
+		return "Int64?", true
+	case ".google.protobuf.UInt64Value":
+		return "UInt64?", true
+	case ".google.protobuf.Int32Value":
+		return "Int32?", true
+	case ".google.protobuf.UInt32Value":
+		return "UInt32?", true
+	case ".google.protobuf.BoolValue":
+		return "Bool?", true
+	case ".google.protobuf.StringValue":
+		return "String?", true
+	case ".google.protobuf.BytesValue":
+		return "Data?", true
+	}
+
+	return "", false
+}
+
+func (t SwiftDataType) repeatedFormat() string {
+	return "[%s]"
+}
+
+func (t SwiftDataType) mapFormat() string {
+	return "[%s: %s]"
+}
+
+func (t SwiftDataType) optionalFormat() string {
+	return "%s?"
+}
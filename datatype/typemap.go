@@ -0,0 +1,213 @@
+package datatype
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	descriptor "google.golang.org/protobuf/types/descriptorpb"
+	"gopkg.in/yaml.v3"
+)
+
+// TypeMap defines a language, or overrides a built-in one, from a YAML or JSON file.
+type TypeMap struct {
+	// Scalars maps a proto scalar type such as "int64" or "bytes" to a type name.
+	Scalars        map[string]string `yaml:"scalars"`
+	RepeatedFormat string            `yaml:"repeated_format"`
+	MapFormat      string            `yaml:"map_format"`
+	OptionalFormat string            `yaml:"optional_format"`
+	// WellKnownTypes maps a google.protobuf type such as "Timestamp" to a type name.
+	WellKnownTypes map[string]string `yaml:"well_known_types"`
+	// Messages maps a fully-qualified message or enum name such as "my.Money" to a type name.
+	Messages map[string]string `yaml:"messages"`
+	// CommentPrefix starts a line comment, e.g. "#", used by the default header.
+	CommentPrefix string `yaml:"comment_prefix"`
+}
+
+func LoadTypeMap(path string) (*TypeMap, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// YAML is a superset of JSON, so both formats are accepted.
+	// Unknown keys are rejected, so that a misspelled key is not silently ignored.
+	var typeMap TypeMap
+	decoder := yaml.NewDecoder(bytes.NewReader(buf))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&typeMap); err != nil {
+		return nil, fmt.Errorf("invalid type map %s: %w", path, err)
+	}
+	if err := typeMap.validate(); err != nil {
+		return nil, fmt.Errorf("invalid type map %s: %w", path, err)
+	}
+
+	return &typeMap, nil
+}
+
+// wellKnownTypeNames are the google.protobuf types that well_known_types may map.
+var wellKnownTypeNames = []string{
+	"Timestamp", "Duration", "Struct", "Value", "ListValue", "Any", "FieldMask", "Empty",
+	"DoubleValue", "FloatValue", "Int64Value", "UInt64Value", "Int32Value", "UInt32Value",
+	"BoolValue", "StringValue", "BytesValue",
+}
+
+// scalarTypeNames returns the proto scalar types that scalars may map, e.g. "int64".
+func scalarTypeNames() []string {
+	var names []string
+	for _, name := range descriptor.FieldDescriptorProto_Type_name {
+		switch name {
+		case "TYPE_GROUP", "TYPE_MESSAGE", "TYPE_ENUM":
+			continue
+		}
+		names = append(names, strings.ToLower(strings.TrimPrefix(name, "TYPE_")))
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// validate rejects formats without the placeholders they are given, and keys naming no proto type.
+func (t *TypeMap) validate() error {
+	formats := []struct {
+		key          string
+		format       string
+		placeholders int
+	}{
+		{key: "repeated_format", format: t.RepeatedFormat, placeholders: 1},
+		{key: "map_format", format: t.MapFormat, placeholders: 2},
+		{key: "optional_format", format: t.OptionalFormat, placeholders: 1},
+	}
+	for _, f := range formats {
+		// %% is a literal percent sign, and any other verb would be given no value.
+		verbs := strings.ReplaceAll(f.format, "%%", "")
+		if f.format != "" && (strings.Count(verbs, "%s") != f.placeholders || strings.Count(verbs, "%") != f.placeholders) {
+			return fmt.Errorf("%s %q must contain exactly %d %%s", f.key, f.format, f.placeholders)
+		}
+	}
+
+	scalars := scalarTypeNames()
+	for _, key := range sortedKeys(t.Scalars) {
+		if !slices.Contains(scalars, key) {
+			return fmt.Errorf("scalars: unknown proto type `%s`, valid types are %s", key, strings.Join(scalars, ", "))
+		}
+	}
+	for _, key := range sortedKeys(t.WellKnownTypes) {
+		if !slices.Contains(wellKnownTypeNames, key) {
+			return fmt.Errorf("well_known_types: unknown well-known type `%s`, valid types are %s", key, strings.Join(wellKnownTypeNames, ", "))
+		}
+	}
+
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+type CustomDataType struct {
+	typeMap *TypeMap
+	// base is the built-in language being overridden, nil for a language defined from scratch.
+	base dataType
+}
+
+func newCustomDataType(typeMap *TypeMap, base dataType) (*CustomDataType, error) {
+	if base == nil && (typeMap.RepeatedFormat == "" || typeMap.MapFormat == "") {
+		return nil, fmt.Errorf("type map must define repeated_format and map_format for a new language")
+	}
+
+	return &CustomDataType{
+		typeMap: typeMap,
+		base:    base,
+	}, nil
+}
+
+func (t CustomDataType) getTypeName(f *descriptor.FieldDescriptorProto) (string, error) {
+	switch f.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE, descriptor.FieldDescriptorProto_TYPE_ENUM:
+		fullName := strings.TrimPrefix(f.GetTypeName(), ".")
+		if typeName, ok := t.typeMap.Messages[fullName]; ok {
+			return typeName, nil
+		}
+		if wellKnownName, ok := strings.CutPrefix(fullName, "google.protobuf."); ok {
+			if typeName, ok := t.typeMap.WellKnownTypes[wellKnownName]; ok {
+				return typeName, nil
+			}
+		}
+	default:
+		protoType := strings.ToLower(strings.TrimPrefix(f.GetType().String(), "TYPE_"))
+		if typeName, ok := t.typeMap.Scalars[protoType]; ok {
+			return typeName, nil
+		}
+	}
+
+	if t.base != nil {
+		return t.base.getTypeName(f)
+	}
+
+	switch f.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+		return messageTypeName(f.GetTypeName()), nil
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		return strings.TrimPrefix(f.GetTypeName(), "."), nil
+	}
+
+	return "", fmt.Errorf("unknown type: %s", f.GetType())
+}
+
+func (t CustomDataType) repeatedFormat() string {
+	if t.typeMap.RepeatedFormat == "" {
+		return t.base.repeatedFormat()
+	}
+
+	return t.typeMap.RepeatedFormat
+}
+
+func (t CustomDataType) mapFormat() string {
+	if t.typeMap.MapFormat == "" {
+		return t.base.mapFormat()
+	}
+
+	return t.typeMap.MapFormat
+}
+
+func (t CustomDataType) optionalFormat() string {
+	if t.typeMap.OptionalFormat != "" {
+		return t.typeMap.OptionalFormat
+	}
+	if t.base != nil {
+		return t.base.optionalFormat()
+	}
+
+	return "%s"
+}
+
+func (t CustomDataType) boxedTypeName(typeName string) string {
+	if b, ok := t.base.(boxer); ok {
+		return b.boxedTypeName(typeName)
+	}
+
+	return typeName
+}
+
+func (t CustomDataType) commentPrefix() string {
+	if t.typeMap.CommentPrefix != "" {
+		return t.typeMap.CommentPrefix
+	}
+	if c, ok := t.base.(commenter); ok {
+		return c.commentPrefix()
+	}
+	if t.base != nil {
+		return "//"
+	}
+
+	return ""
+}
@@ -0,0 +1,162 @@
+package datatype_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/deresmos/protoc-gen-template/datatype"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	descriptor "google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestTypeMapExpressesBuiltinTypeScript(t *testing.T) {
+	builtin, err := datatype.NewDataType("typescript", datatype.Option{})
+	assert.NoError(t, err)
+	custom, err := datatype.NewDataType("tsmap", datatype.Option{TypeMapPath: "../example/typemap/typescript.yaml"})
+	assert.NoError(t, err)
+
+	var fields []*descriptor.FieldDescriptorProto
+	for fieldType := range descriptor.FieldDescriptorProto_Type_name {
+		fieldType := descriptor.FieldDescriptorProto_Type(fieldType)
+		if fieldType == descriptor.FieldDescriptorProto_TYPE_GROUP {
+			continue
+		}
+		field := newField(fieldType, descriptor.FieldDescriptorProto_LABEL_REPEATED)
+		field.TypeName = proto.String(".Tests.NestTests")
+		fields = append(fields, field)
+	}
+	for _, name := range []string{"Timestamp", "Duration", "Struct", "Int64Value", "StringValue"} {
+		field := newField(descriptor.FieldDescriptorProto_TYPE_MESSAGE, descriptor.FieldDescriptorProto_LABEL_OPTIONAL)
+		field.TypeName = proto.String(".google.protobuf." + name)
+		fields = append(fields, field)
+	}
+
+	for _, field := range fields {
+		t.Run(field.GetType().String()+field.GetTypeName(), func(t *testing.T) {
+			want, err := builtin.GetName(field)
+			assert.NoError(t, err)
+			got, err := custom.GetName(field)
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestTypeMapOverridesBuiltin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "override.yaml")
+	err := os.WriteFile(path, []byte("scalars:\n  int64: BigInt\nmessages:\n  my.Money: Decimal\n"), 0o644)
+	assert.NoError(t, err)
+
+	dataType, err := datatype.NewDataType("dart", datatype.Option{TypeMapPath: path})
+	assert.NoError(t, err)
+
+	int64Field := newField(descriptor.FieldDescriptorProto_TYPE_INT64, descriptor.FieldDescriptorProto_LABEL_REPEATED)
+	got, err := dataType.GetName(int64Field)
+	assert.NoError(t, err)
+	assert.Equal(t, "List<BigInt>", got)
+
+	moneyField := newField(descriptor.FieldDescriptorProto_TYPE_MESSAGE, descriptor.FieldDescriptorProto_LABEL_OPTIONAL)
+	moneyField.TypeName = proto.String(".my.Money")
+	got, err = dataType.GetName(moneyField)
+	assert.NoError(t, err)
+	assert.Equal(t, "Decimal", got)
+
+	stringField := newField(descriptor.FieldDescriptorProto_TYPE_STRING, descriptor.FieldDescriptorProto_LABEL_OPTIONAL)
+	got, err = dataType.GetName(stringField)
+	assert.NoError(t, err)
+	assert.Equal(t, "String", got)
+}
+
+func TestTypeMapNewLanguageRequiresFormats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "new.json")
+	err := os.WriteFile(path, []byte(`{"scalars": {"string": "str"}}`), 0o644)
+	assert.NoError(t, err)
+
+	_, err = datatype.NewDataType("unknown", datatype.Option{TypeMapPath: path})
+	assert.Error(t, err)
+}
+
+func TestTypeMapRejectsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "typo.yaml")
+	err := os.WriteFile(path, []byte("scalar:\n  string: Text\n"), 0o644)
+	assert.NoError(t, err)
+
+	_, err = datatype.NewDataType("typescript", datatype.Option{TypeMapPath: path})
+	assert.ErrorContains(t, err, "field scalar not found")
+}
+
+func TestTypeMapRejectsInvalidEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		typeMap string
+		wantErr string
+	}{
+		{
+			name:    "repeated format without placeholder",
+			typeMap: "repeated_format: \"List\"\n",
+			wantErr: "repeated_format \"List\" must contain exactly 1 %s",
+		},
+		{
+			name:    "map format with one placeholder",
+			typeMap: "map_format: \"Map<%s>\"\n",
+			wantErr: "map_format \"Map<%s>\" must contain exactly 2 %s",
+		},
+		{
+			name:    "optional format with another verb",
+			typeMap: "optional_format: \"%s | %d\"\n",
+			wantErr: "optional_format \"%s | %d\" must contain exactly 1 %s",
+		},
+		{
+			name:    "misspelled scalar",
+			typeMap: "scalars:\n  int46: BigInt\n",
+			wantErr: "scalars: unknown proto type `int46`",
+		},
+		{
+			name:    "misspelled well-known type",
+			typeMap: "well_known_types:\n  TimeStamp: Date\n",
+			wantErr: "well_known_types: unknown well-known type `TimeStamp`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "invalid.yaml")
+			err := os.WriteFile(path, []byte(tt.typeMap), 0o644)
+			assert.NoError(t, err)
+
+			_, err = datatype.NewDataType("typescript", datatype.Option{TypeMapPath: path})
+			assert.ErrorContains(t, err, "invalid type map "+path)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestCommentPrefix(t *testing.T) {
+	overridePath := filepath.Join(t.TempDir(), "override.yaml")
+	err := os.WriteFile(overridePath, []byte("scalars:\n  int64: BigInt\n"), 0o644)
+	assert.NoError(t, err)
+	newPath := filepath.Join(t.TempDir(), "new.yaml")
+	err = os.WriteFile(newPath, []byte("repeated_format: \"[%s]\"\nmap_format: \"{%s: %s}\"\n"), 0o644)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		lang   string
+		option datatype.Option
+		want   string
+	}{
+		{name: "built-in", lang: "typescript", want: "//"},
+		{name: "built-in python", lang: "python", want: "#"},
+		{name: "override keeps built-in", lang: "python", option: datatype.Option{TypeMapPath: overridePath}, want: "#"},
+		{name: "new language from type map", lang: "elixir", option: datatype.Option{TypeMapPath: "../test/datatype/elixir.json"}, want: "#"},
+		{name: "new language without prefix", lang: "unknown", option: datatype.Option{TypeMapPath: newPath}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataType, err := datatype.NewDataType(tt.lang, tt.option)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, dataType.CommentPrefix())
+		})
+	}
+}
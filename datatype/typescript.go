@@ -2,47 +2,101 @@ package datatype
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
 
 	descriptor "google.golang.org/protobuf/types/descriptorpb"
 )
 
-type TypeScriptDataType struct{}
+type TypeScriptDataType struct {
+	// longType is the type of 64-bit integers: "number", "bigint" or "string".
+	longType string
+}
 
 func (t TypeScriptDataType) getTypeName(f *descriptor.FieldDescriptorProto) (string, error) {
 	switch f.GetType() {
 	case descriptor.FieldDescriptorProto_TYPE_STRING:
 		return "string", nil
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		return "Uint8Array", nil
 	case descriptor.FieldDescriptorProto_TYPE_INT32,
 		descriptor.FieldDescriptorProto_TYPE_UINT32,
 		descriptor.FieldDescriptorProto_TYPE_SINT32,
-		descriptor.FieldDescriptorProto_TYPE_INT64,
-		descriptor.FieldDescriptorProto_TYPE_UINT64,
-		descriptor.FieldDescriptorProto_TYPE_SINT64:
-		return "number", nil
-	case descriptor.FieldDescriptorProto_TYPE_DOUBLE,
+		descriptor.FieldDescriptorProto_TYPE_FIXED32,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED32,
+		descriptor.FieldDescriptorProto_TYPE_DOUBLE,
 		descriptor.FieldDescriptorProto_TYPE_FLOAT:
-		return "bigint", nil
+		return "number", nil
+	case descriptor.FieldDescriptorProto_TYPE_INT64,
+		descriptor.FieldDescriptorProto_TYPE_UINT64,
+		descriptor.FieldDescriptorProto_TYPE_SINT64,
+		descriptor.FieldDescriptorProto_TYPE_FIXED64,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+		return t.longType, nil
 	case descriptor.FieldDescriptorProto_TYPE_BOOL:
 		return "boolean", nil
 	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
-		switch f.GetTypeName() {
-		case ".google.protobuf.Timestamp":
-			return "Date", nil
-		default:
-			if strings.Contains(f.GetTypeName(), "__") {
-				re := regexp.MustCompile(`\.__(\w+)$`)
-				return re.FindStringSubmatch(f.GetTypeName())[1], nil
-			}
-
-			return strings.TrimPrefix(f.GetTypeName(), "."), nil
+		if typeName, ok := t.wellKnownTypeName(f.GetTypeName()); ok {
+			return typeName, nil
 		}
+
+		return messageTypeName(f.GetTypeName()), nil
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		return strings.TrimPrefix(f.GetTypeName(), "."), nil
 	}
 
 	return "", fmt.Errorf("unknown type: %s", f.GetType())
 }
 
+func (t TypeScriptDataType) wellKnownTypeName(typeName string) (string, bool) {
+	switch typeName {
+	case ".google.protobuf.Timestamp":
+		return "Date", true
+	case ".google.protobuf.Duration":
+		return "string", true
+	case ".google.protobuf.Struct":
+		return "Record<string, unknown>", true
+	case ".google.protobuf.Value":
+		return "unknown", true
+	case ".google.protobuf.ListValue":
+		return "unknown[]", true
+	case ".google.protobuf.Any":
+		return "Record<string, unknown>", true
+	case ".google.protobuf.FieldMask":
+		return "string[]", true
+	case ".google.protobuf.Empty":
+		return "Record<string, never>", true
+	case ".google.protobuf.DoubleValue":
+		return "number | null", true
+	case ".google.protobuf.FloatValue":
+		return "number | null", true
+	case ".google.protobuf.Int64Value":
+		return t.longType + " | null", true
+	case ".google.protobuf.UInt64Value":
+		return t.longType + " | null", true
+	case ".google.protobuf.Int32Value":
+		return "number | null", true
+	case ".google.protobuf.UInt32Value":
+		return "number | null", true
+	case ".google.protobuf.BoolValue":
+		return "boolean | null", true
+	case ".google.protobuf.StringValue":
+		return "string | null", true
+	case ".google.protobuf.BytesValue":
+		return "Uint8Array | null", true
+	}
+
+	return "", false
+}
+
 func (t TypeScriptDataType) repeatedFormat() string {
 	return "%s[]"
 }
+
+func (t TypeScriptDataType) mapFormat() string {
+	return "Record<%s, %s>"
+}
+
+// optionalFormat leaves proto3 optional fields to templates, which check IsOptional.
+func (t TypeScriptDataType) optionalFormat() string {
+	return "%s"
+}
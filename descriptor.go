@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"slices"
 	"strings"
 
@@ -9,9 +10,67 @@ import (
 )
 
 type FileDescriptor struct {
+	// PackageName is kept for existing templates and equals Package.
 	PackageName string
 	Messages    []MessageDescriptor
 	Services    []ServiceDescriptor
+	Enums       []EnumDescriptor
+	FileInfo
+	Comments
+}
+
+// FileInfo describes the proto file a descriptor was declared in.
+type FileInfo struct {
+	Package  string
+	FileName string
+	// Syntax is "proto2", "proto3" or "editions", in which case Edition is set (e.g. "2023").
+	Syntax       string
+	Edition      string
+	Dependencies []string
+	Options      FileOptions
+}
+
+type FileOptions struct {
+	GoPackage          string
+	JavaPackage        string
+	JavaOuterClassname string
+	JavaMultipleFiles  bool
+	CsharpNamespace    string
+	ObjcClassPrefix    string
+	PhpNamespace       string
+	RubyPackage        string
+	SwiftPrefix        string
+}
+
+func newFileInfo(f *descriptor.FileDescriptorProto) *FileInfo {
+	syntax := f.GetSyntax()
+	if syntax == "" {
+		syntax = "proto2"
+	}
+	var edition string
+	if syntax == "editions" {
+		edition = strings.TrimPrefix(f.GetEdition().String(), "EDITION_")
+	}
+
+	options := f.GetOptions()
+	return &FileInfo{
+		Package:      f.GetPackage(),
+		FileName:     f.GetName(),
+		Syntax:       syntax,
+		Edition:      edition,
+		Dependencies: f.GetDependency(),
+		Options: FileOptions{
+			GoPackage:          options.GetGoPackage(),
+			JavaPackage:        options.GetJavaPackage(),
+			JavaOuterClassname: options.GetJavaOuterClassname(),
+			JavaMultipleFiles:  options.GetJavaMultipleFiles(),
+			CsharpNamespace:    options.GetCsharpNamespace(),
+			ObjcClassPrefix:    options.GetObjcClassPrefix(),
+			PhpNamespace:       options.GetPhpNamespace(),
+			RubyPackage:        options.GetRubyPackage(),
+			SwiftPrefix:        options.GetSwiftPrefix(),
+		},
+	}
 }
 
 func (f *FileDescriptor) Append(fileDescriptor *FileDescriptor) *FileDescriptor {
@@ -21,18 +80,26 @@ func (f *FileDescriptor) Append(fileDescriptor *FileDescriptor) *FileDescriptor
 
 	return &FileDescriptor{
 		PackageName: f.PackageName,
-		Messages:    append(f.Messages, fileDescriptor.Messages...),
-		Services:    append(f.Services, fileDescriptor.Services...),
+		Messages:    slices.Concat(f.Messages, fileDescriptor.Messages),
+		Services:    slices.Concat(f.Services, fileDescriptor.Services),
+		Enums:       slices.Concat(f.Enums, fileDescriptor.Enums),
+		FileInfo:    f.FileInfo,
+		Comments:    f.Comments,
 	}
 }
 
 type MessageDescriptor struct {
 	MessageName   string
+	FullName      string
 	Fields        MessageFieldDescriptorList
 	Parents       MessageDescriptorList
 	ItemMessages  MessageDescriptorList
 	Children      MessageDescriptorList
+	Enums         []EnumDescriptor
+	Oneofs        []OneofDescriptor
 	IsItemMessage bool
+	File          *FileInfo
+	Comments
 }
 
 type MessageDescriptorList []MessageDescriptor
@@ -47,28 +114,103 @@ func (m MessageDescriptorList) GetByMessageName(name string) *MessageDescriptor
 	return nil
 }
 
+func (m MessageDescriptorList) GetByFullName(fullName string) *MessageDescriptor {
+	for _, message := range m {
+		if message.FullName == fullName {
+			return &message
+		}
+	}
+
+	return nil
+}
+
 type MessageFieldDescriptor struct {
-	FieldName     string
-	DataTypeName  string
-	IsOptional    bool
-	IsRequired    bool
-	IsTimestamp   bool
-	IsRepeated    bool
-	IsMessageType bool
+	FieldName    string
+	DataTypeName string
+	// ProtoType is the proto field type, e.g. "int64", "bytes", "message" or "enum".
+	ProtoType string
+	// TypeFullName is the fully-qualified name of a message or enum field type.
+	TypeFullName string
+	IsOptional   bool
+	IsRequired   bool
+	IsTimestamp  bool
+	// IsWellKnownType is set for google.protobuf message types, e.g. WellKnownTypeName "Duration".
+	IsWellKnownType   bool
+	WellKnownTypeName string
+	IsRepeated        bool
+	IsMessageType     bool
+	IsEnumType        bool
+	IsOneofMember     bool
+	OneofName         string
+	// IsMap fields describe their value type through ProtoType, IsMessageType and IsEnumType.
+	IsMap            bool
+	MapKeyTypeName   string
+	MapValueTypeName string
+	Comments
+	// generator resolves the message or enum of the field on demand, since messages may refer to themselves.
+	generator *FileDescriptorGenerator
+}
+
+// Message returns the message of a message field, or of the values of a map field,
+// declared in any file of the request. It is nil for other fields.
+func (f MessageFieldDescriptor) Message() (*MessageDescriptor, error) {
+	if !f.IsMessageType || f.generator == nil {
+		return nil, nil
+	}
+
+	return f.generator.resolveMessage(f.TypeFullName)
+}
+
+// Enum returns the enum of an enum field, or of the values of a map field,
+// declared in any file of the request. It is nil for other fields.
+func (f MessageFieldDescriptor) Enum() (*EnumDescriptor, error) {
+	if !f.IsEnumType || f.generator == nil {
+		return nil, nil
+	}
+
+	return f.generator.resolveEnum(f.TypeFullName)
 }
 
 type MessageFieldDescriptorList []MessageFieldDescriptor
 
 func (m MessageFieldDescriptorList) HasTimestamp() bool {
+	return m.HasWellKnownType("Timestamp")
+}
+
+// HasWellKnownType reports whether any field is of the given well-known type, e.g. "Duration".
+func (m MessageFieldDescriptorList) HasWellKnownType(name string) bool {
 	return slices.ContainsFunc(m, func(field MessageFieldDescriptor) bool {
-		return field.IsTimestamp
+		return field.WellKnownTypeName == name
 	})
 }
 
+type OneofDescriptor struct {
+	OneofName string
+	Fields    MessageFieldDescriptorList
+	Comments
+}
+
+type EnumDescriptor struct {
+	EnumName string
+	FullName string
+	Values   []EnumValueDescriptor
+	Parents  MessageDescriptorList
+	File     *FileInfo
+	Comments
+}
+
+type EnumValueDescriptor struct {
+	ValueName string
+	Number    int32
+	Comments
+}
+
 type ServiceDescriptor struct {
 	ServiceName string
 	Methods     []ServiceMethodDescriptor
 	Messages    MessageDescriptorList
+	File        *FileInfo
+	Comments
 }
 
 type ServiceMethodDescriptor struct {
@@ -76,24 +218,35 @@ type ServiceMethodDescriptor struct {
 	ServiceName   string
 	InputMessage  *MessageDescriptor
 	OutputMessage *MessageDescriptor
-	Dependencies  []MessageDescriptor
+	// Dependencies holds the input, the output and every message reachable from them,
+	// ordered so that each message comes after the messages it refers to.
+	Dependencies []MessageDescriptor
+	// StreamingKind is one of "unary", "client", "server" or "bidi".
+	StreamingKind     string
+	IsClientStreaming bool
+	IsServerStreaming bool
+	IsBidiStreaming   bool
+	File              *FileInfo
+	Comments
 }
 
 type FileDescriptorGenerator struct {
-	packageName string
-	dataType    *datatype.DataType
-	option      generatorOption
+	dataType     *datatype.DataType
+	registry     *typeRegistry
+	messageCache map[string]MessageDescriptorList
+	option       generatorOption
 }
 
 type generatorOption struct {
 	EnableMessageFlatten bool
 }
 
-func NewFileDescriptorGenerator(packageName string, dataType *datatype.DataType, option generatorOption) *FileDescriptorGenerator {
+func NewFileDescriptorGenerator(dataType *datatype.DataType, registry *typeRegistry, option generatorOption) *FileDescriptorGenerator {
 	return &FileDescriptorGenerator{
-		packageName: packageName,
-		dataType:    dataType,
-		option:      option,
+		dataType:     dataType,
+		registry:     registry,
+		messageCache: make(map[string]MessageDescriptorList),
+		option:       option,
 	}
 }
 
@@ -109,27 +262,56 @@ func messageFlatten(message *MessageDescriptor) []MessageDescriptor {
 	return messages
 }
 
+// allMessages flattens messages including item messages, for lookups by name.
+func allMessages(messages []MessageDescriptor) MessageDescriptorList {
+	var list MessageDescriptorList
+	for _, message := range messages {
+		list = append(list, message)
+		list = append(list, allMessages(message.Children)...)
+	}
+	return list
+}
+
+func enumFlatten(message *MessageDescriptor) []EnumDescriptor {
+	enums := append([]EnumDescriptor{}, message.Enums...)
+	for _, child := range message.Children {
+		enums = append(enums, enumFlatten(&child)...)
+	}
+	return enums
+}
+
 func (g *FileDescriptorGenerator) Run(f *descriptor.FileDescriptorProto) (*FileDescriptor, error) {
-	types, err := g.generateMessageDescriptor(f.MessageType, nil)
+	file := newFileContext(f)
+	types, err := g.generateMessageDescriptor(f.MessageType, nil, file, []int32{fileMessageTypePath})
 	if err != nil {
 		return nil, err
 	}
+	g.messageCache[f.GetName()] = allMessages(types)
+
+	enums := g.generateEnumDescriptor(f.EnumType, nil, file, []int32{fileEnumTypePath})
 
 	var newTypes []MessageDescriptor
 	if g.option.EnableMessageFlatten {
 		for _, message := range types {
 			newTypes = append(newTypes, messageFlatten(&message)...)
+			enums = append(enums, enumFlatten(&message)...)
 		}
 
 		types = newTypes
 	}
 
-	services := g.generateServiceDescriptor(f.Service, MessageDescriptorList(types))
+	services, err := g.generateServiceDescriptor(f.Service, MessageDescriptorList(types), file)
+	if err != nil {
+		return nil, err
+	}
 
 	return &FileDescriptor{
-		PackageName: g.packageName,
+		PackageName: f.GetPackage(),
 		Messages:    types,
 		Services:    services,
+		Enums:       enums,
+		FileInfo:    *file.info,
+		Comments:    file.locations.comments([]int32{fileSyntaxPath}),
 	}, nil
 }
 
@@ -137,21 +319,66 @@ func isItemMessage(name string) bool {
 	return strings.HasPrefix(name, "__")
 }
 
-func (g *FileDescriptorGenerator) generateMessageDescriptor(messageTypes []*descriptor.DescriptorProto, parents []MessageDescriptor) ([]MessageDescriptor, error) {
+func protoType(field *descriptor.FieldDescriptorProto) string {
+	return strings.ToLower(strings.TrimPrefix(field.GetType().String(), "TYPE_"))
+}
+
+func isWellKnownMessage(field *descriptor.FieldDescriptorProto) bool {
+	return field.GetType() == descriptor.FieldDescriptorProto_TYPE_MESSAGE && isWellKnownType(strings.TrimPrefix(field.GetTypeName(), "."))
+}
+
+func wellKnownTypeName(field *descriptor.FieldDescriptorProto) string {
+	if !isWellKnownMessage(field) {
+		return ""
+	}
+
+	return strings.TrimPrefix(field.GetTypeName(), ".google.protobuf.")
+}
+
+func isMapEntry(messageType *descriptor.DescriptorProto) bool {
+	return messageType.GetOptions().GetMapEntry()
+}
+
+// findMapEntry returns the map entry of a map field, nested in the message named fullName.
+func findMapEntry(messageType *descriptor.DescriptorProto, fullName string, field *descriptor.FieldDescriptorProto) *descriptor.DescriptorProto {
+	if field.GetType() != descriptor.FieldDescriptorProto_TYPE_MESSAGE || field.GetLabel() != descriptor.FieldDescriptorProto_LABEL_REPEATED {
+		return nil
+	}
+
+	for _, nestedType := range messageType.GetNestedType() {
+		if isMapEntry(nestedType) && field.GetTypeName() == "."+qualifiedName(fullName, nestedType.GetName()) {
+			return nestedType
+		}
+	}
+
+	return nil
+}
+
+func (g *FileDescriptorGenerator) generateMessageDescriptor(messageTypes []*descriptor.DescriptorProto, parents []MessageDescriptor, file *fileContext, path []int32) ([]MessageDescriptor, error) {
 	var types []MessageDescriptor
 
-	for _, messageType := range messageTypes {
-		fields, err := g.generateMessageFieldDescriptors(messageType.GetField())
+	for i, messageType := range messageTypes {
+		if isMapEntry(messageType) {
+			continue
+		}
+
+		messagePath := appendPath(path, int32(i))
+		fullName := file.fullName(parents, messageType.GetName())
+		fields, err := g.generateMessageFieldDescriptors(messageType, fullName, file, messagePath)
 		if err != nil {
 			return nil, err
 		}
 		newMessageType := MessageDescriptor{
 			MessageName:   messageType.GetName(),
+			FullName:      fullName,
 			Fields:        fields,
+			Oneofs:        generateOneofDescriptors(fields, messageType.GetOneofDecl(), file.locations, appendPath(messagePath, messageOneofPath)),
 			Parents:       parents,
 			IsItemMessage: isItemMessage(messageType.GetName()),
+			File:          file.info,
+			Comments:      file.locations.comments(messagePath),
 		}
-		nestedTypes, err := g.generateMessageDescriptor(messageType.NestedType, append(parents, newMessageType))
+		nestedTypes, err := g.generateMessageDescriptor(messageType.NestedType, append(parents, newMessageType), file, appendPath(messagePath, messageNestedPath))
 		if err != nil {
 			return nil, err
 		}
@@ -165,6 +392,7 @@ func (g *FileDescriptorGenerator) generateMessageDescriptor(messageTypes []*desc
 		}
 		newMessageType.ItemMessages = itemMessages
 		newMessageType.Children = nestedTypes
+		newMessageType.Enums = g.generateEnumDescriptor(messageType.EnumType, append(parents, newMessageType), file, appendPath(messagePath, messageEnumTypePath))
 
 		types = append(types, newMessageType)
 	}
@@ -172,22 +400,47 @@ func (g *FileDescriptorGenerator) generateMessageDescriptor(messageTypes []*desc
 	return types, nil
 }
 
-func (g *FileDescriptorGenerator) generateMessageFieldDescriptors(fields []*descriptor.FieldDescriptorProto) ([]MessageFieldDescriptor, error) {
+func (g *FileDescriptorGenerator) generateMessageFieldDescriptors(messageType *descriptor.DescriptorProto, fullName string, file *fileContext, path []int32) ([]MessageFieldDescriptor, error) {
 	var params []MessageFieldDescriptor
-	for _, field := range fields {
+	for i, field := range messageType.GetField() {
+		comments := file.locations.comments(appendPath(path, messageFieldPath, int32(i)))
+		if mapEntry := findMapEntry(messageType, fullName, field); mapEntry != nil {
+			param, err := g.generateMapFieldDescriptor(field, mapEntry, comments)
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, param)
+			continue
+		}
+
 		typeName, err := g.dataType.GetName(field)
 		if err != nil {
 			return nil, err
 		}
 
+		// proto3 optional fields are wrapped in a synthetic oneof, which is not a real union.
+		var oneofName string
+		if field.OneofIndex != nil && !field.GetProto3Optional() {
+			oneofName = messageType.GetOneofDecl()[field.GetOneofIndex()].GetName()
+		}
+
 		param := MessageFieldDescriptor{
-			FieldName:     field.GetName(),
-			DataTypeName:  typeName,
-			IsOptional:    field.GetProto3Optional(),
-			IsRequired:    !field.GetProto3Optional(),
-			IsTimestamp:   field.GetTypeName() == ".google.protobuf.Timestamp",
-			IsRepeated:    field.GetLabel() == descriptor.FieldDescriptorProto_LABEL_REPEATED,
-			IsMessageType: field.GetType() == descriptor.FieldDescriptorProto_TYPE_MESSAGE,
+			FieldName:         field.GetName(),
+			DataTypeName:      typeName,
+			ProtoType:         protoType(field),
+			TypeFullName:      strings.TrimPrefix(field.GetTypeName(), "."),
+			IsOptional:        field.GetProto3Optional(),
+			IsRequired:        !field.GetProto3Optional(),
+			IsTimestamp:       field.GetTypeName() == ".google.protobuf.Timestamp",
+			IsWellKnownType:   isWellKnownMessage(field),
+			WellKnownTypeName: wellKnownTypeName(field),
+			IsRepeated:        field.GetLabel() == descriptor.FieldDescriptorProto_LABEL_REPEATED,
+			IsMessageType:     field.GetType() == descriptor.FieldDescriptorProto_TYPE_MESSAGE,
+			IsEnumType:        field.GetType() == descriptor.FieldDescriptorProto_TYPE_ENUM,
+			IsOneofMember:     oneofName != "",
+			OneofName:         oneofName,
+			Comments:          comments,
+			generator:         g,
 		}
 		params = append(params, param)
 	}
@@ -195,33 +448,292 @@ func (g *FileDescriptorGenerator) generateMessageFieldDescriptors(fields []*desc
 	return params, nil
 }
 
-func (g *FileDescriptorGenerator) generateServiceDescriptor(services []*descriptor.ServiceDescriptorProto, messages MessageDescriptorList) []ServiceDescriptor {
+func (g *FileDescriptorGenerator) generateMapFieldDescriptor(field *descriptor.FieldDescriptorProto, mapEntry *descriptor.DescriptorProto, comments Comments) (MessageFieldDescriptor, error) {
+	// Map entries always have the key as field 1 and the value as field 2.
+	key, value := mapEntry.GetField()[0], mapEntry.GetField()[1]
+	typeName, err := g.dataType.GetMapName(key, value)
+	if err != nil {
+		return MessageFieldDescriptor{}, err
+	}
+	keyTypeName, err := g.dataType.GetName(key)
+	if err != nil {
+		return MessageFieldDescriptor{}, err
+	}
+	valueTypeName, err := g.dataType.GetName(value)
+	if err != nil {
+		return MessageFieldDescriptor{}, err
+	}
+
+	return MessageFieldDescriptor{
+		FieldName:         field.GetName(),
+		DataTypeName:      typeName,
+		ProtoType:         protoType(value),
+		TypeFullName:      strings.TrimPrefix(value.GetTypeName(), "."),
+		IsRequired:        true,
+		IsTimestamp:       value.GetTypeName() == ".google.protobuf.Timestamp",
+		IsWellKnownType:   isWellKnownMessage(value),
+		WellKnownTypeName: wellKnownTypeName(value),
+		IsMessageType:     value.GetType() == descriptor.FieldDescriptorProto_TYPE_MESSAGE,
+		IsEnumType:        value.GetType() == descriptor.FieldDescriptorProto_TYPE_ENUM,
+		IsMap:             true,
+		MapKeyTypeName:    keyTypeName,
+		MapValueTypeName:  valueTypeName,
+		Comments:          comments,
+		generator:         g,
+	}, nil
+}
+
+func generateOneofDescriptors(fields []MessageFieldDescriptor, oneofDecls []*descriptor.OneofDescriptorProto, locations sourceLocations, path []int32) []OneofDescriptor {
+	var oneofs []OneofDescriptor
+	for i, oneofDecl := range oneofDecls {
+		var members MessageFieldDescriptorList
+		for _, field := range fields {
+			if field.OneofName == oneofDecl.GetName() {
+				members = append(members, field)
+			}
+		}
+
+		// Synthetic oneofs of proto3 optional fields have no members here.
+		if len(members) == 0 {
+			continue
+		}
+
+		oneofs = append(oneofs, OneofDescriptor{
+			OneofName: oneofDecl.GetName(),
+			Fields:    members,
+			Comments:  locations.comments(appendPath(path, int32(i))),
+		})
+	}
+
+	return oneofs
+}
+
+func (g *FileDescriptorGenerator) generateEnumDescriptor(enumTypes []*descriptor.EnumDescriptorProto, parents []MessageDescriptor, file *fileContext, path []int32) []EnumDescriptor {
+	var enums []EnumDescriptor
+
+	for i, enumType := range enumTypes {
+		enumPath := appendPath(path, int32(i))
+		var values []EnumValueDescriptor
+		for j, value := range enumType.GetValue() {
+			values = append(values, EnumValueDescriptor{
+				ValueName: value.GetName(),
+				Number:    value.GetNumber(),
+				Comments:  file.locations.comments(appendPath(enumPath, enumValuePath, int32(j))),
+			})
+		}
+
+		enums = append(enums, EnumDescriptor{
+			EnumName: enumType.GetName(),
+			FullName: file.fullName(parents, enumType.GetName()),
+			Values:   values,
+			Parents:  parents,
+			File:     file.info,
+			Comments: file.locations.comments(enumPath),
+		})
+	}
+
+	return enums
+}
+
+func (g *FileDescriptorGenerator) generateServiceDescriptor(services []*descriptor.ServiceDescriptorProto, messages MessageDescriptorList, file *fileContext) ([]ServiceDescriptor, error) {
 	var types []ServiceDescriptor
 
-	for _, service := range services {
-		methods := g.generateServiceMethodDescriptors(service, messages)
+	for i, service := range services {
+		servicePath := []int32{fileServicePath, int32(i)}
+		methods, err := g.generateServiceMethodDescriptors(service, file, servicePath)
+		if err != nil {
+			return nil, err
+		}
 		newService := ServiceDescriptor{
 			ServiceName: strings.TrimSuffix(service.GetName(), "Service"),
 			Methods:     methods,
 			Messages:    messages,
+			File:        file.info,
+			Comments:    file.locations.comments(servicePath),
 		}
 		types = append(types, newService)
 	}
 
-	return types
+	return types, nil
 }
 
-func (g *FileDescriptorGenerator) generateServiceMethodDescriptors(service *descriptor.ServiceDescriptorProto, messages MessageDescriptorList) []ServiceMethodDescriptor {
+func streamingKind(method *descriptor.MethodDescriptorProto) string {
+	switch {
+	case method.GetClientStreaming() && method.GetServerStreaming():
+		return "bidi"
+	case method.GetClientStreaming():
+		return "client"
+	case method.GetServerStreaming():
+		return "server"
+	}
+
+	return "unary"
+}
+
+func (g *FileDescriptorGenerator) generateServiceMethodDescriptors(service *descriptor.ServiceDescriptorProto, file *fileContext, path []int32) ([]ServiceMethodDescriptor, error) {
 	var params []ServiceMethodDescriptor
-	for _, method := range service.Method {
+	for i, method := range service.Method {
+		inputMessage, err := g.resolveMessage(method.GetInputType())
+		if err != nil {
+			return nil, err
+		}
+		outputMessage, err := g.resolveMessage(method.GetOutputType())
+		if err != nil {
+			return nil, err
+		}
+
+		dependencies, err := g.resolveDependencies(inputMessage, outputMessage)
+		if err != nil {
+			return nil, err
+		}
+
 		param := ServiceMethodDescriptor{
-			MethodName:    method.GetName(),
-			ServiceName:   strings.TrimSuffix(service.GetName(), "Service"),
-			InputMessage:  messages.GetByMessageName(strings.TrimPrefix(method.GetInputType(), ".")),
-			OutputMessage: messages.GetByMessageName(strings.TrimPrefix(method.GetOutputType(), ".")),
+			MethodName:        method.GetName(),
+			ServiceName:       strings.TrimSuffix(service.GetName(), "Service"),
+			InputMessage:      inputMessage,
+			OutputMessage:     outputMessage,
+			Dependencies:      dependencies,
+			StreamingKind:     streamingKind(method),
+			IsClientStreaming: method.GetClientStreaming(),
+			IsServerStreaming: method.GetServerStreaming(),
+			IsBidiStreaming:   method.GetClientStreaming() && method.GetServerStreaming(),
+			File:              file.info,
+			Comments:          file.locations.comments(appendPath(path, serviceMethodPath, int32(i))),
 		}
 		params = append(params, param)
 	}
 
-	return params
+	return params, nil
+}
+
+// fileMessages returns every message of a file, including nested ones, generated once per file.
+func (g *FileDescriptorGenerator) fileMessages(f *descriptor.FileDescriptorProto) (MessageDescriptorList, error) {
+	if messages, ok := g.messageCache[f.GetName()]; ok {
+		return messages, nil
+	}
+
+	types, err := g.generateMessageDescriptor(f.MessageType, nil, newFileContext(f), []int32{fileMessageTypePath})
+	if err != nil {
+		return nil, err
+	}
+	messages := allMessages(types)
+	g.messageCache[f.GetName()] = messages
+
+	return messages, nil
+}
+
+// resolveMessage looks up a message by its fully-qualified type name in any file of the request.
+func (g *FileDescriptorGenerator) resolveMessage(typeName string) (*MessageDescriptor, error) {
+	fullName := strings.TrimPrefix(typeName, ".")
+	f, ok := g.registry.lookupFile(fullName)
+	if !ok {
+		return nil, fmt.Errorf("message `%s` not found", fullName)
+	}
+
+	messages, err := g.fileMessages(f)
+	if err != nil {
+		return nil, err
+	}
+
+	message := messages.GetByFullName(fullName)
+	if message == nil {
+		return nil, fmt.Errorf("message `%s` not found", fullName)
+	}
+
+	return message, nil
+}
+
+// resolveEnum looks up an enum by its fully-qualified type name in any file of the request.
+func (g *FileDescriptorGenerator) resolveEnum(typeName string) (*EnumDescriptor, error) {
+	fullName := strings.TrimPrefix(typeName, ".")
+	f, ok := g.registry.lookupFile(fullName)
+	if !ok {
+		return nil, fmt.Errorf("enum `%s` not found", fullName)
+	}
+
+	messages, err := g.fileMessages(f)
+	if err != nil {
+		return nil, err
+	}
+
+	enums := g.generateEnumDescriptor(f.EnumType, nil, newFileContext(f), []int32{fileEnumTypePath})
+	for _, message := range messages {
+		enums = append(enums, message.Enums...)
+	}
+	for i := range enums {
+		if enums[i].FullName == fullName {
+			return &enums[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("enum `%s` not found", fullName)
+}
+
+// wellKnownTypes are the google.protobuf messages that templates map to native types.
+// Other google.protobuf messages, such as those of descriptor.proto, are regular messages.
+var wellKnownTypes = map[string]bool{
+	"google.protobuf.Timestamp":   true,
+	"google.protobuf.Duration":    true,
+	"google.protobuf.Struct":      true,
+	"google.protobuf.Value":       true,
+	"google.protobuf.ListValue":   true,
+	"google.protobuf.Any":         true,
+	"google.protobuf.FieldMask":   true,
+	"google.protobuf.Empty":       true,
+	"google.protobuf.DoubleValue": true,
+	"google.protobuf.FloatValue":  true,
+	"google.protobuf.Int64Value":  true,
+	"google.protobuf.UInt64Value": true,
+	"google.protobuf.Int32Value":  true,
+	"google.protobuf.UInt32Value": true,
+	"google.protobuf.BoolValue":   true,
+	"google.protobuf.StringValue": true,
+	"google.protobuf.BytesValue":  true,
+}
+
+func isWellKnownType(fullName string) bool {
+	return wellKnownTypes[fullName]
+}
+
+// resolveDependencies collects the transitive closure of messages referred to by fields,
+// skipping well-known types which templates map to native types, even as a method input or output.
+func (g *FileDescriptorGenerator) resolveDependencies(messages ...*MessageDescriptor) ([]MessageDescriptor, error) {
+	var dependencies []MessageDescriptor
+	visited := make(map[string]bool)
+
+	var visit func(message *MessageDescriptor) error
+	visit = func(message *MessageDescriptor) error {
+		if visited[message.FullName] {
+			return nil
+		}
+		visited[message.FullName] = true
+
+		for _, field := range message.Fields {
+			if !field.IsMessageType || field.IsWellKnownType {
+				continue
+			}
+
+			fieldMessage, err := g.resolveMessage(field.TypeFullName)
+			if err != nil {
+				return err
+			}
+			if err := visit(fieldMessage); err != nil {
+				return err
+			}
+		}
+
+		dependencies = append(dependencies, *message)
+		return nil
+	}
+
+	for _, message := range messages {
+		if isWellKnownType(message.FullName) {
+			continue
+		}
+		if err := visit(message); err != nil {
+			return nil, err
+		}
+	}
+
+	return dependencies, nil
 }
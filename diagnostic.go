@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// templateErrorPattern matches the "template: name:line:col: " prefix of text/template
+// errors, where the column is only reported for execution errors.
+var templateErrorPattern = regexp.MustCompile(`^template: (.+?):(\d+):(?:(\d+):)? (.*)$`)
+
+// templateError describes a failure of a template file with a snippet of the offending line.
+type templateError struct {
+	file    string
+	line    int
+	column  int
+	message string
+	snippet string
+}
+
+func (e *templateError) Error() string {
+	position := fmt.Sprintf("%s:%d", e.file, e.line)
+	if e.column >= 0 {
+		position += fmt.Sprintf(":%d", e.column+1)
+	}
+	if e.snippet == "" {
+		return fmt.Sprintf("%s: %s", position, e.message)
+	}
+
+	lineNumber := strconv.Itoa(e.line)
+	message := fmt.Sprintf("%s: %s\n\t%s | %s", position, e.message, lineNumber, e.snippet)
+	if e.column >= 0 {
+		message += fmt.Sprintf("\n\t%s | %s^", strings.Repeat(" ", len(lineNumber)), caretIndent(e.snippet, e.column))
+	}
+
+	return message
+}
+
+// newTemplateError wraps a text/template error of the template file with its position and source line.
+// Errors without a position, such as those of template functions, are returned as is.
+func newTemplateError(file string, source string, err error) error {
+	match := templateErrorPattern.FindStringSubmatch(err.Error())
+	if match == nil || match[1] != file {
+		return err
+	}
+
+	line, _ := strconv.Atoi(match[2])
+	column := -1
+	if match[3] != "" {
+		column, _ = strconv.Atoi(match[3])
+	}
+	message := match[4]
+	// Drop the template name text/template repeats in execution errors.
+	if _, after, ok := strings.Cut(message, fmt.Sprintf("executing %q ", file)); ok {
+		message = after
+	}
+
+	snippet := ""
+	if lines := strings.Split(source, "\n"); line <= len(lines) {
+		snippet = lines[line-1]
+	}
+
+	return &templateError{
+		file:    file,
+		line:    line,
+		column:  column,
+		message: message,
+		snippet: snippet,
+	}
+}
+
+// caretIndent returns the whitespace placing a caret under the byte column of line, keeping tabs.
+func caretIndent(line string, column int) string {
+	if column > len(line) {
+		column = len(line)
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return r
+		}
+		return ' '
+	}, line[:column])
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"text/template"
+)
+
+// fileDirectives are set by the template while rendering a file, overriding the options for that file:
+//
+//	{{ setOverwrite false }}
+//	{{ skipFile }}
+//	{{ setOutputPath "./gen/user_impl.ts" }}
+type fileDirectives struct {
+	overwrite  OverwriteMode
+	skip       bool
+	outputPath string
+	// header is the header option, which setOverwrite "if_unmodified" needs.
+	header string
+}
+
+func (d *fileDirectives) funcMap() template.FuncMap {
+	return template.FuncMap{
+		"setOverwrite":  d.setOverwrite,
+		"skipFile":      d.skipFile,
+		"setOutputPath": d.setOutputPath,
+	}
+}
+
+// setOverwrite takes a bool, or an overwrite mode such as "merge".
+func (d *fileDirectives) setOverwrite(overwrite any) (string, error) {
+	var mode OverwriteMode
+	switch v := overwrite.(type) {
+	case bool:
+		mode = OverwriteNever
+		if v {
+			mode = OverwriteAlways
+		}
+	case string:
+		var err error
+		mode, err = parseOverwriteMode(v)
+		if err != nil {
+			return "", err
+		}
+		if _, err := parseHeader(d.header, mode); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("invalid overwrite %v, expected a bool or string", overwrite)
+	}
+	d.overwrite = mode
+
+	return "", nil
+}
+
+func (d *fileDirectives) skipFile() string {
+	d.skip = true
+	return ""
+}
+
+func (d *fileDirectives) setOutputPath(outputPath string) string {
+	d.outputPath = outputPath
+	return ""
+}
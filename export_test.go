@@ -0,0 +1,4 @@
+package main
+
+// MergeLines exposes mergeLines to the tests of package main_test.
+var MergeLines = mergeLines
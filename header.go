@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// checksumLabel marks the checksum rendered by the header, so other digests in a file are left alone.
+const checksumLabel = "checksum: "
+
+// checksumPlaceholder stands for the checksum while it is computed, so a file can be verified
+// by hashing it with its checksum replaced by the placeholder.
+var checksumPlaceholder = checksumLabel + "sha256:" + strings.Repeat("0", sha256.Size*2)
+
+var checksumPattern = regexp.MustCompile(checksumLabel + `sha256:[0-9a-f]{64}`)
+
+// headerData is given to the header template.
+type headerData struct {
+	ProtoFile  string
+	Template   string
+	Descriptor string
+	// Checksum renders as `checksum: sha256:<hex>`, which overwrite=if_unmodified needs to detect edits.
+	Checksum string
+}
+
+// initHeaderTemplate returns the default header for header=true, commented with commentPrefix,
+// or the header template file otherwise.
+func initHeaderTemplate(header string, commentPrefix string) (*template.Template, error) {
+	if header == "true" {
+		if commentPrefix == "" {
+			return nil, fmt.Errorf("the default header needs comment_prefix in the type map")
+		}
+		return template.New("header").Parse(commentPrefix + " Code generated by protoc-gen-template from {{.ProtoFile}}, template {{.Template}}. DO NOT EDIT.\n" +
+			commentPrefix + " {{.Checksum}}\n\n")
+	}
+
+	buf, err := os.ReadFile(header)
+	if err != nil {
+		return nil, err
+	}
+
+	return template.New(header).Parse(string(buf))
+}
+
+// rendersChecksum reports whether the header template renders the checksum.
+func rendersChecksum(headerTemplate *template.Template) bool {
+	header, err := renderHeader(headerTemplate, &generatedFile{
+		protoFile:  "example.proto",
+		template:   "example.template",
+		descriptor: "message Example",
+	})
+
+	return err == nil && strings.Contains(header, checksumPlaceholder)
+}
+
+// renderHeader renders the header of a file, with a placeholder for the checksum stamped later.
+func renderHeader(headerTemplate *template.Template, file *generatedFile) (string, error) {
+	b := bytes.NewBuffer([]byte{})
+	err := headerTemplate.Execute(b, headerData{
+		ProtoFile:  file.protoFile,
+		Template:   file.template,
+		Descriptor: file.descriptor,
+		Checksum:   checksumPlaceholder,
+	})
+	if err != nil {
+		return "", fmt.Errorf("header: %w", err)
+	}
+
+	return b.String(), nil
+}
+
+// stampChecksum replaces the checksum of content, the first one as the header comes first,
+// with the hash of the content itself.
+func stampChecksum(content string) string {
+	loc := checksumPattern.FindStringIndex(content)
+	if loc == nil {
+		return content
+	}
+	unstamped := content[:loc[0]] + checksumPlaceholder + content[loc[1]:]
+	sum := sha256.Sum256([]byte(unstamped))
+	checksum := checksumLabel + "sha256:" + hex.EncodeToString(sum[:])
+
+	return content[:loc[0]] + checksum + content[loc[1]:]
+}
+
+// stampChecksums stamps the final content of the files whose header rendered the checksum,
+// so a body is never stamped in place of a header.
+func stampChecksums(files []*generatedFile) {
+	for _, file := range files {
+		if file.checksum {
+			file.file.Content = proto.String(stampChecksum(file.file.GetContent()))
+		}
+	}
+}
+
+// isUnmodified reports whether content still matches its checksum.
+func isUnmodified(content string) bool {
+	return checksumPattern.MatchString(content) && stampChecksum(content) == content
+}
+
+// filterUnmodifiedOutputFiles skips the existing files edited since they were generated.
+func filterUnmodifiedOutputFiles(files []*generatedFile) ([]*generatedFile, error) {
+	for _, file := range files {
+		if file.overwrite != OverwriteIfUnmodified || file.skipReason != "" {
+			continue
+		}
+		existing, err := os.ReadFile(file.destination())
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if !isUnmodified(string(existing)) {
+			log.Printf("Skip generate %s. Because it was modified since it was generated.", file.destination())
+			file.skipReason = "file was modified since it was generated"
+		}
+	}
+
+	return files, nil
+}
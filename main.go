@@ -2,9 +2,12 @@ package main
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"io"
 	"log"
 	"os"
+	"strings"
 	"text/template"
 
 	"github.com/deresmos/protoc-gen-template/datatype"
@@ -27,180 +30,311 @@ func parseReq(r io.Reader) (*plugin.CodeGeneratorRequest, error) {
 }
 
 type fileGenerator struct {
-	packageName             string // TODO:
-	option                  *ProtoOption
-	fileDescriptorGenerator *FileDescriptorGenerator
-	fileTemplate            *template.Template
-	outputPathTemplate      *template.Template
+	option             *ProtoOption
+	fileTemplate       *template.Template
+	templateSource     string
+	outputPathTemplate *template.Template
+	headerTemplate     *template.Template
+	// headerChecksum reports whether the header renders the checksum, which overwrite=if_unmodified needs.
+	headerChecksum bool
 }
 
-func (g *fileGenerator) run(fileDescriptor *FileDescriptor) ([]*plugin.CodeGeneratorResponse_File, error) {
-	var files []*plugin.CodeGeneratorResponse_File
+// run renders every target descriptor of the file, collecting the errors of all of them.
+func (g *fileGenerator) run(fileDescriptor *FileDescriptor) ([]*generatedFile, error) {
+	var files []*generatedFile
+	var errs []error
+	generate := func(fileName string, kind string, name string, data any) {
+		if !g.option.isTarget(name) {
+			return
+		}
+		responseFile, directives, err := g.generateResponseFile(data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s %s: %w", fileName, kind, name, err))
+			return
+		}
+		file := &generatedFile{
+			file:       responseFile,
+			protoFile:  fileName,
+			descriptor: kind + " " + name,
+			template:   g.option.TemplatePath,
+			overwrite:  g.option.Overwrite,
+			outDir:     g.option.OutDir,
+		}
+		if directives.overwrite != "" {
+			file.overwrite = directives.overwrite
+		}
+		if file.overwrite == OverwriteIfUnmodified && !g.headerChecksum {
+			errs = append(errs, fmt.Errorf("%s: %s %s: overwrite `%s` requires the header to render {{.Checksum}}", fileName, kind, name, file.overwrite))
+			return
+		}
+		if directives.skip {
+			file.skipReason = "skipped by the template"
+		}
+		if g.headerTemplate != nil {
+			header, err := renderHeader(g.headerTemplate, file)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s %s: %w", fileName, kind, name, err))
+				return
+			}
+			file.file.Content = proto.String(header + responseFile.GetContent())
+			file.checksum = strings.Contains(header, checksumPlaceholder)
+		}
+		files = append(files, file)
+	}
+
 	switch g.option.GenerateType {
 	case "message":
 		for _, message := range fileDescriptor.Messages {
-			responseFile, err := g.generateResponseFile(message)
-			if err != nil {
-				return nil, err
-			}
-			files = append(files, responseFile)
+			generate(message.File.FileName, "message", message.MessageName, message)
 		}
 	case "service":
 		for _, service := range fileDescriptor.Services {
-			responseFile, err := g.generateResponseFile(service)
-			if err != nil {
-				return nil, err
-			}
-			files = append(files, responseFile)
+			generate(service.File.FileName, "service", service.ServiceName, service)
 		}
 	case "method":
 		for _, service := range fileDescriptor.Services {
 			for _, method := range service.Methods {
-				responseFile, err := g.generateResponseFile(method)
-				if err != nil {
-					return nil, err
-				}
-				files = append(files, responseFile)
+				generate(method.File.FileName, "method", method.MethodName, method)
 			}
 		}
-	case "file":
-		responseFile, err := g.generateResponseFile(fileDescriptor)
-		if err != nil {
-			return nil, err
+	case "enum":
+		for _, enum := range fileDescriptor.Enums {
+			generate(enum.File.FileName, "enum", enum.EnumName, enum)
 		}
-		files = append(files, responseFile)
+	case "file":
+		generate(fileDescriptor.FileName, "file", fileDescriptor.FileName, fileDescriptor)
+	default:
+		return nil, fmt.Errorf("unknown generate_type `%s`", g.option.GenerateType)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
 	}
 
-	files = filterResponseFiles(files, func(file *plugin.CodeGeneratorResponse_File) bool {
-		return file.GetName() != ""
-	})
-
-	return files, nil
-}
-
-func filterResponseFiles(files []*plugin.CodeGeneratorResponse_File, filter func(*plugin.CodeGeneratorResponse_File) bool) []*plugin.CodeGeneratorResponse_File {
-	var newFiles []*plugin.CodeGeneratorResponse_File
+	// An empty output path lets a template skip a descriptor.
 	for _, file := range files {
-		if filter(file) {
-			newFiles = append(newFiles, file)
+		if file.skipReason == "" && file.file.GetName() == "" {
+			file.skipReason = "output path is empty"
 		}
 	}
-	return newFiles
+
+	return files, nil
 }
 
-func (g *fileGenerator) generateResponseFile(data any) (*plugin.CodeGeneratorResponse_File, error) {
-	b := bytes.NewBuffer([]byte{})
-	err := g.fileTemplate.Execute(b, data)
+func (g *fileGenerator) generateResponseFile(data any) (*plugin.CodeGeneratorResponse_File, *fileDirectives, error) {
+	// Each render binds its own directives to a clone, leaving the parsed template untouched.
+	tmpl, err := g.fileTemplate.Clone()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	outputPathBuffer := bytes.NewBuffer([]byte{})
-	err = g.outputPathTemplate.Execute(outputPathBuffer, data)
+	directives := &fileDirectives{header: g.option.Header}
+	b := bytes.NewBuffer([]byte{})
+	err = tmpl.Funcs(directives.funcMap()).Execute(b, data)
 	if err != nil {
-		return nil, err
+		return nil, nil, newTemplateError(g.option.TemplatePath, g.templateSource, err)
+	}
+
+	outputPath := directives.outputPath
+	if outputPath == "" {
+		outputPathBuffer := bytes.NewBuffer([]byte{})
+		err = g.outputPathTemplate.Execute(outputPathBuffer, data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("output_path: %w", err)
+		}
+		outputPath = outputPathBuffer.String()
 	}
 
-	outputPath := outputPathBuffer.String()
 	return &plugin.CodeGeneratorResponse_File{
 		Name:    &outputPath,
 		Content: proto.String(b.String()),
-	}, nil
+	}, directives, nil
 }
 
+// processReq generates the files of every target. Failures are reported through
+// the response error, so protoc prints them instead of a stack trace.
 func processReq(req *plugin.CodeGeneratorRequest) *plugin.CodeGeneratorResponse {
-	protoOption, err := NewProtoOptionFromString(req.GetParameter())
+	var resp plugin.CodeGeneratorResponse
+	features := uint64(plugin.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+	resp.SupportedFeatures = &features
+
+	protoOptions, err := NewProtoOptionsFromString(req.GetParameter())
 	if err != nil {
-		panic(err)
+		resp.Error = proto.String(fmt.Sprintf("invalid parameter: %s", err))
+		return &resp
+	}
+
+	files := make(map[string]*descriptor.FileDescriptorProto)
+	for _, f := range req.ProtoFile {
+		files[f.GetName()] = f
+	}
+
+	registry := newTypeRegistry(req.GetProtoFile())
+	// Targets with the same data type options share their data type and parsed file descriptors.
+	dataTypes := make(map[string]*datatype.DataType)
+	fileDescriptors := make(map[string]map[string]*FileDescriptor)
+	var generatedFiles []*generatedFile
+	var errs []error
+	for _, protoOption := range protoOptions {
+		key := protoOption.dataTypeKey()
+		descriptors, ok := fileDescriptors[key]
+		if !ok {
+			dataTypes[key], err = datatype.NewDataType(protoOption.Language, datatype.Option{
+				LongType:    protoOption.LongType,
+				TypeMapPath: protoOption.TypeMap,
+			})
+			if err == nil {
+				descriptors, err = generateFileDescriptors(req, files, registry, dataTypes[key], protoOption)
+			} else {
+				err = fmt.Errorf("lang %s: %w", protoOption.Language, err)
+			}
+			if err != nil {
+				errs = append(errs, err)
+				descriptors = nil
+			}
+			fileDescriptors[key] = descriptors
+		}
+		if descriptors == nil {
+			// Already reported by the first target sharing these descriptors.
+			continue
+		}
+
+		targetFiles, err := generateTargetFiles(req, descriptors, dataTypes[key].CommentPrefix(), protoOption)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		generatedFiles = append(generatedFiles, targetFiles...)
 	}
-	dataType, err := datatype.NewDataType(protoOption.Language)
+	if err := applyProtectedRegions(generatedFiles); err != nil {
+		errs = append(errs, err)
+	}
+	mergeBaseFiles, err := mergeExistingFiles(generatedFiles)
 	if err != nil {
-		panic(err)
+		errs = append(errs, err)
+	}
+	generatedFiles = append(generatedFiles, mergeBaseFiles...)
+	stampChecksums(generatedFiles)
+	if len(errs) > 0 {
+		resp.Error = proto.String(errors.Join(errs...).Error())
+		return &resp
+	}
+
+	if protoOptions[0].DryRun {
+		manifest, err := manifestResponseFile(generatedFiles)
+		if err != nil {
+			resp.Error = proto.String(err.Error())
+			return &resp
+		}
+		resp.File = append(resp.File, manifest)
+		return &resp
 	}
+	resp.File = writtenFiles(generatedFiles)
+
+	return &resp
+}
 
-	fileDescriptorGenerator := NewFileDescriptorGenerator(req.GetParameter(), dataType, generatorOption{
+func generateFileDescriptors(req *plugin.CodeGeneratorRequest, files map[string]*descriptor.FileDescriptorProto, registry *typeRegistry, dataType *datatype.DataType, protoOption *ProtoOption) (map[string]*FileDescriptor, error) {
+	fileDescriptorGenerator := NewFileDescriptorGenerator(dataType, registry, generatorOption{
 		EnableMessageFlatten: protoOption.enableMessageFlatten,
 	})
-	fileTmpl, err := initFileTemplate(protoOption.TemplatePath)
+	descriptors := make(map[string]*FileDescriptor)
+	var errs []error
+	for _, fname := range req.FileToGenerate {
+		fileDescriptor, err := fileDescriptorGenerator.Run(files[fname])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", fname, err))
+			continue
+		}
+		descriptors[fname] = fileDescriptor
+	}
+
+	return descriptors, errors.Join(errs...)
+}
+
+func generateTargetFiles(req *plugin.CodeGeneratorRequest, descriptors map[string]*FileDescriptor, commentPrefix string, protoOption *ProtoOption) ([]*generatedFile, error) {
+	source, err := os.ReadFile(protoOption.TemplatePath)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("template %s: %w", protoOption.TemplatePath, err)
 	}
-	outputTmpl, err := initOutputPathTemplate(protoOption.OutputPath)
+	fileTmpl, err := initFileTemplate(protoOption.TemplatePath, string(source), protoOption.Strict)
 	if err != nil {
-		panic(err)
+		return nil, newTemplateError(protoOption.TemplatePath, string(source), err)
+	}
+	outputTmpl, err := initOutputPathTemplate(protoOption.OutputPath, protoOption.Strict)
+	if err != nil {
+		return nil, fmt.Errorf("output_path %s: %w", protoOption.OutputPath, err)
 	}
 
 	fileGenerator := &fileGenerator{
-		packageName:             req.GetParameter(),
-		option:                  protoOption,
-		fileDescriptorGenerator: fileDescriptorGenerator,
-		fileTemplate:            fileTmpl,
-		outputPathTemplate:      outputTmpl,
+		option:             protoOption,
+		fileTemplate:       fileTmpl,
+		templateSource:     string(source),
+		outputPathTemplate: outputTmpl,
 	}
-
-	files := make(map[string]*descriptor.FileDescriptorProto)
-	for _, f := range req.ProtoFile {
-		files[f.GetName()] = f
+	if protoOption.Header != "" {
+		fileGenerator.headerTemplate, err = initHeaderTemplate(protoOption.Header, commentPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("header %s: %w", protoOption.Header, err)
+		}
+		fileGenerator.headerChecksum = rendersChecksum(fileGenerator.headerTemplate)
+		if protoOption.Overwrite == OverwriteIfUnmodified && !fileGenerator.headerChecksum {
+			return nil, fmt.Errorf("header %s: overwrite `%s` requires the header to render {{.Checksum}}", protoOption.Header, protoOption.Overwrite)
+		}
 	}
-	var resp plugin.CodeGeneratorResponse
-	features := uint64(plugin.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
-	resp.SupportedFeatures = &features
 
 	if protoOption.AllowMerge {
 		var megeredFileDescriptor *FileDescriptor
 		for _, fname := range req.FileToGenerate {
-			f := files[fname]
-			fileDescriptor, err := fileGenerator.fileDescriptorGenerator.Run(f)
-			if err != nil {
-				panic(err)
-			}
-
-			megeredFileDescriptor = megeredFileDescriptor.Append(fileDescriptor)
+			megeredFileDescriptor = megeredFileDescriptor.Append(descriptors[fname])
 		}
 
 		files, err := fileGenerator.run(megeredFileDescriptor)
 		if err != nil {
-			panic(err)
-		}
-		if !protoOption.Overwrite {
-			files = filterFirstTimeOutputFiles(files)
+			return nil, err
 		}
-		resp.File = append(resp.File, files...)
-	} else {
-		for _, fname := range req.FileToGenerate {
-			f := files[fname]
-			fileDescriptor, err := fileGenerator.fileDescriptorGenerator.Run(f)
-			if err != nil {
-				panic(err)
-			}
+		return filterOutputFiles(files)
+	}
 
-			files, err := fileGenerator.run(fileDescriptor)
-			if err != nil {
-				panic(err)
-			}
-			if !protoOption.Overwrite {
-				files = filterFirstTimeOutputFiles(files)
-			}
-			resp.File = append(resp.File, files...)
+	var targetFiles []*generatedFile
+	var errs []error
+	for _, fname := range req.FileToGenerate {
+		files, err := fileGenerator.run(descriptors[fname])
+		if err != nil {
+			errs = append(errs, err)
+			continue
 		}
+		files, err = filterOutputFiles(files)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		targetFiles = append(targetFiles, files...)
 	}
 
-	return &resp
+	return targetFiles, errors.Join(errs...)
 }
 
-func filterFirstTimeOutputFiles(files []*plugin.CodeGeneratorResponse_File) []*plugin.CodeGeneratorResponse_File {
-	var newFiles []*plugin.CodeGeneratorResponse_File
+// filterOutputFiles skips the existing files that the overwrite mode of each file keeps.
+func filterOutputFiles(files []*generatedFile) ([]*generatedFile, error) {
+	files = filterFirstTimeOutputFiles(files)
+
+	return filterUnmodifiedOutputFiles(files)
+}
+
+// filterFirstTimeOutputFiles skips the files that already exist.
+func filterFirstTimeOutputFiles(files []*generatedFile) []*generatedFile {
 	for _, file := range files {
+		if file.overwrite != OverwriteNever || file.skipReason != "" {
+			continue
+		}
 		// パスが存在するかチェック
-		_, err := os.Stat(file.GetName())
+		_, err := os.Stat(file.destination())
 		if !os.IsNotExist(err) {
-			log.Printf("Skip generate %s. Bacause overwrite option is false.", file.GetName())
-			continue
+			log.Printf("Skip generate %s. Bacause overwrite option is false.", file.destination())
+			file.skipReason = "file exists and overwrite is false"
 		}
-
-		newFiles = append(newFiles, file)
 	}
 
-	return newFiles
+	return files
 }
 
 func emitResp(resp *plugin.CodeGeneratorResponse) error {
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	plugin "google.golang.org/protobuf/types/pluginpb"
+)
+
+// mergeBasePath returns the sidecar file holding the last generated content of an output file.
+func mergeBasePath(name string) string {
+	return path.Join(path.Dir(name), "."+path.Base(name)+".generated")
+}
+
+// mergeExistingFiles merges the edits made to existing overwrite=merge files into the new render,
+// and returns the sidecar files recording the render as the base of the next merge.
+func mergeExistingFiles(files []*generatedFile) ([]*generatedFile, error) {
+	var baseFiles []*generatedFile
+	var errs []error
+	for _, file := range files {
+		if file.overwrite != OverwriteMerge || file.skipReason != "" {
+			continue
+		}
+		name := file.file.GetName()
+		rendered := file.file.GetContent()
+
+		existing, err := os.ReadFile(file.destination())
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+		if err == nil {
+			base, err := os.ReadFile(mergeBasePath(file.destination()))
+			switch {
+			case errors.Is(err, os.ErrNotExist):
+				// Without a base the edits are unknown, so the file is kept and the render becomes the base.
+				log.Printf("Skip generate %s. Because it has no merge base, the render is recorded as one.", name)
+				file.skipReason = "file exists without a merge base"
+			case err != nil:
+				errs = append(errs, err)
+				continue
+			default:
+				merged, conflict := mergeLines(string(base), string(existing), rendered)
+				if conflict {
+					log.Printf("Merge conflicts in %s.", name)
+				}
+				file.file.Content = &merged
+			}
+		}
+
+		baseFiles = append(baseFiles, &generatedFile{
+			file: &plugin.CodeGeneratorResponse_File{
+				Name:    proto.String(mergeBasePath(name)),
+				Content: proto.String(rendered),
+			},
+			protoFile:  file.protoFile,
+			descriptor: file.descriptor,
+			template:   file.template,
+			overwrite:  OverwriteAlways,
+			outDir:     file.outDir,
+		})
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("merge: %w", errors.Join(errs...))
+	}
+
+	return baseFiles, nil
+}
+
+// mergeHunk replaces the lines [start, end) of the base with lines.
+type mergeHunk struct {
+	start int
+	end   int
+	lines []string
+}
+
+// mergeLines applies both the edits from base to existing and from base to rendered.
+// Edits touching the same lines are kept side by side between conflict markers.
+func mergeLines(base string, existing string, rendered string) (string, bool) {
+	baseLines := splitLines(base)
+	existingHunks := diffLines(baseLines, splitLines(existing))
+	renderedHunks := diffLines(baseLines, splitLines(rendered))
+
+	var merged []string
+	conflict := false
+	position := 0
+	i, j := 0, 0
+	for i < len(existingHunks) || j < len(renderedHunks) {
+		// Group the hunks of both sides that overlap or touch, starting from the first one.
+		var existingGroup, renderedGroup []mergeHunk
+		var start, end int
+		if j >= len(renderedHunks) || i < len(existingHunks) && existingHunks[i].start <= renderedHunks[j].start {
+			start, end = existingHunks[i].start, existingHunks[i].end
+		} else {
+			start, end = renderedHunks[j].start, renderedHunks[j].end
+		}
+		for {
+			if i < len(existingHunks) && existingHunks[i].start <= end {
+				existingGroup = append(existingGroup, existingHunks[i])
+				end = max(end, existingHunks[i].end)
+				i++
+				continue
+			}
+			if j < len(renderedHunks) && renderedHunks[j].start <= end {
+				renderedGroup = append(renderedGroup, renderedHunks[j])
+				end = max(end, renderedHunks[j].end)
+				j++
+				continue
+			}
+			break
+		}
+
+		merged = append(merged, baseLines[position:start]...)
+		existingLines := applyHunks(baseLines, start, end, existingGroup)
+		renderedLines := applyHunks(baseLines, start, end, renderedGroup)
+		switch {
+		case len(existingGroup) == 0:
+			merged = append(merged, renderedLines...)
+		case len(renderedGroup) == 0, strings.Join(existingLines, "") == strings.Join(renderedLines, ""):
+			merged = append(merged, existingLines...)
+		default:
+			conflict = true
+			merged = append(merged, "<<<<<<< existing\n")
+			merged = append(merged, terminateLines(existingLines)...)
+			merged = append(merged, "=======\n")
+			merged = append(merged, terminateLines(renderedLines)...)
+			merged = append(merged, ">>>>>>> generated\n")
+		}
+		position = end
+	}
+	merged = append(merged, baseLines[position:]...)
+
+	return strings.Join(merged, ""), conflict
+}
+
+// diffLines returns the hunks turning base into other, from their longest common subsequence.
+func diffLines(base []string, other []string) []mergeHunk {
+	d := &lineDiff{a: base, b: other}
+	d.compare(0, len(base), 0, len(other))
+
+	var hunks []mergeHunk
+	i, j := 0, 0
+	for _, match := range append(d.matches, [2]int{len(base), len(other)}) {
+		if i < match[0] || j < match[1] {
+			hunks = append(hunks, mergeHunk{start: i, end: match[0], lines: other[j:match[1]]})
+		}
+		i, j = match[0]+1, match[1]+1
+	}
+
+	return hunks
+}
+
+// lineDiff finds the common lines of a and b with Myers' linear space algorithm,
+// so large generated files do not need a table of every pair of lines.
+type lineDiff struct {
+	a       []string
+	b       []string
+	matches [][2]int
+}
+
+// compare appends the matching lines of a[aLow:aHigh] and b[bLow:bHigh] in order.
+func (d *lineDiff) compare(aLow int, aHigh int, bLow int, bHigh int) {
+	for aLow < aHigh && bLow < bHigh && d.a[aLow] == d.b[bLow] {
+		d.matches = append(d.matches, [2]int{aLow, bLow})
+		aLow++
+		bLow++
+	}
+	suffix := 0
+	for aLow < aHigh && bLow < bHigh && d.a[aHigh-1] == d.b[bHigh-1] {
+		aHigh--
+		bHigh--
+		suffix++
+	}
+
+	if aLow < aHigh && bLow < bHigh {
+		xStart, yStart, xEnd, yEnd := d.middleSnake(aLow, aHigh, bLow, bHigh)
+		d.compare(aLow, xStart, bLow, yStart)
+		for x, y := xStart, yStart; x < xEnd; x, y = x+1, y+1 {
+			d.matches = append(d.matches, [2]int{x, y})
+		}
+		d.compare(xEnd, aHigh, yEnd, bHigh)
+	}
+
+	for i := 0; i < suffix; i++ {
+		d.matches = append(d.matches, [2]int{aHigh + i, bHigh + i})
+	}
+}
+
+// middleSnake returns the run of common lines in the middle of a shortest edit script,
+// searching from both ends until the paths overlap.
+func (d *lineDiff) middleSnake(aLow int, aHigh int, bLow int, bHigh int) (int, int, int, int) {
+	n, m := aHigh-aLow, bHigh-bLow
+	delta := n - m
+	odd := delta%2 != 0
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+	forward := make([]int, 2*offset+1)
+	backward := make([]int, 2*offset+1)
+
+	for step := 0; step <= maxD; step++ {
+		for k := -step; k <= step; k += 2 {
+			var x int
+			if k == -step || k != step && forward[offset+k-1] < forward[offset+k+1] {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			xStart, yStart := x, y
+			for x < n && y < m && d.a[aLow+x] == d.b[bLow+y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+			if reverse := delta - k; odd && reverse >= -(step-1) && reverse <= step-1 && x+backward[offset+reverse] >= n {
+				return aLow + xStart, bLow + yStart, aLow + x, bLow + y
+			}
+		}
+		for k := -step; k <= step; k += 2 {
+			var x int
+			if k == -step || k != step && backward[offset+k-1] < backward[offset+k+1] {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			xStart, yStart := x, y
+			for x < n && y < m && d.a[aHigh-1-x] == d.b[bHigh-1-y] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+			if reverse := delta - k; !odd && reverse >= -step && reverse <= step && x+forward[offset+reverse] >= n {
+				return aHigh - x, bHigh - y, aHigh - xStart, bHigh - yStart
+			}
+		}
+	}
+
+	// Unreachable: the searches always meet within maxD steps.
+	return aLow, bLow, aLow, bLow
+}
+
+// applyHunks returns the base lines [start, end) with the hunks applied.
+func applyHunks(base []string, start int, end int, hunks []mergeHunk) []string {
+	var lines []string
+	position := start
+	for _, hunk := range hunks {
+		lines = append(lines, base[position:hunk.start]...)
+		lines = append(lines, hunk.lines...)
+		position = hunk.end
+	}
+
+	return append(lines, base[position:end]...)
+}
+
+// splitLines splits s into lines keeping their line breaks.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// terminateLines ensures the last line ends with a line break, so a conflict marker follows on its own line.
+func terminateLines(lines []string) []string {
+	if len(lines) > 0 && !strings.HasSuffix(lines[len(lines)-1], "\n") {
+		lines = append(lines[:len(lines)-1:len(lines)-1], lines[len(lines)-1]+"\n")
+	}
+
+	return lines
+}
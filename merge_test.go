@@ -0,0 +1,102 @@
+package main_test
+
+import (
+	"testing"
+
+	templatefunc "github.com/deresmos/protoc-gen-template"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeLines(t *testing.T) {
+	tests := []struct {
+		name         string
+		base         string
+		existing     string
+		rendered     string
+		want         string
+		wantConflict bool
+	}{
+		{
+			name:     "No edits",
+			base:     "a\nb\n",
+			existing: "a\nb\n",
+			rendered: "a\nb\n",
+			want:     "a\nb\n",
+		},
+		{
+			name:     "Edits on separate lines",
+			base:     "a\nb\nc\n",
+			existing: "A\nb\nc\n",
+			rendered: "a\nb\nC\n",
+			want:     "A\nb\nC\n",
+		},
+		{
+			name:     "Insertion and deletion on separate lines",
+			base:     "a\nb\nc\nd\n",
+			existing: "a\nx\nb\nc\nd\n",
+			rendered: "a\nb\nc\n",
+			want:     "a\nx\nb\nc\n",
+		},
+		{
+			name:     "Identical edits on both sides",
+			base:     "a\nb\nc\n",
+			existing: "a\nB\nc\nd\n",
+			rendered: "a\nB\nc\nd\n",
+			want:     "a\nB\nc\nd\n",
+		},
+		{
+			name:     "Edits spread through the file on both sides",
+			base:     "1\n2\n3\n4\n5\n6\n7\n8\n",
+			existing: "0\n1\n2\nthree\n4\n5\n6\n7\n8\n",
+			rendered: "1\n2\n3\n4\n6\nsix\n7\n8\n9\n",
+			want:     "0\n1\n2\nthree\n4\n6\nsix\n7\n8\n9\n",
+		},
+		{
+			name:         "Insertions on both sides at the same position",
+			base:         "a\nb\n",
+			existing:     "a\nx\nb\n",
+			rendered:     "a\ny\nb\n",
+			want:         "a\n<<<<<<< existing\nx\n=======\ny\n>>>>>>> generated\nb\n",
+			wantConflict: true,
+		},
+		{
+			name:         "Adjacent hunks conflict",
+			base:         "a\nb\nc\n",
+			existing:     "A\nb\nc\n",
+			rendered:     "a\nB\nc\n",
+			want:         "<<<<<<< existing\nA\nb\n=======\na\nB\n>>>>>>> generated\nc\n",
+			wantConflict: true,
+		},
+		{
+			name:         "Edit against deletion",
+			base:         "a\nb\nc\n",
+			existing:     "a\nB\nc\n",
+			rendered:     "a\nc\n",
+			want:         "a\n<<<<<<< existing\nB\n=======\n>>>>>>> generated\nc\n",
+			wantConflict: true,
+		},
+		{
+			name:     "Missing trailing newline",
+			base:     "a\nb",
+			existing: "x\na\nb",
+			rendered: "a\nc",
+			want:     "x\na\nc",
+		},
+		{
+			name:         "Conflict on a last line without newline",
+			base:         "a\nb",
+			existing:     "a\nB",
+			rendered:     "a\nC",
+			want:         "a\n<<<<<<< existing\nB\n=======\nC\n>>>>>>> generated\n",
+			wantConflict: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflict := templatefunc.MergeLines(tt.base, tt.existing, tt.rendered)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantConflict, conflict)
+		})
+	}
+}
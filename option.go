@@ -2,69 +2,162 @@ package main
 
 import (
 	"fmt"
-	"strings"
+	"path"
 )
 
+// OverwriteMode decides what happens to an output file that already exists.
+type OverwriteMode string
+
+const (
+	OverwriteAlways OverwriteMode = "true"
+	OverwriteNever  OverwriteMode = "false"
+	// OverwriteMerge merges the changes made to the file since it was last generated.
+	OverwriteMerge OverwriteMode = "merge"
+	// OverwriteIfUnmodified overwrites the files whose header checksum still matches their content.
+	OverwriteIfUnmodified OverwriteMode = "if_unmodified"
+)
+
+func parseOverwriteMode(value string) (OverwriteMode, error) {
+	switch mode := OverwriteMode(value); mode {
+	case "":
+		return OverwriteAlways, nil // Default true
+	case OverwriteAlways, OverwriteNever, OverwriteMerge, OverwriteIfUnmodified:
+		return mode, nil
+	}
+
+	return "", fmt.Errorf("invalid overwrite `%s`, expected true, false, merge or if_unmodified", value)
+}
+
+// parseHeader validates the header option, which overwrite=if_unmodified needs for its checksum.
+func parseHeader(value string, overwrite OverwriteMode) (string, error) {
+	if value == "false" {
+		value = ""
+	}
+	if value == "" && overwrite == OverwriteIfUnmodified {
+		return "", fmt.Errorf("overwrite `%s` requires the header option", overwrite)
+	}
+
+	return value, nil
+}
+
 type ProtoOption struct {
 	TemplatePath         string
 	Language             string
 	OutputPath           string
 	GenerateType         string
 	AllowMerge           bool
-	Overwrite            bool
+	Overwrite            OverwriteMode
+	LongType             string
+	TypeMap              string
+	Strict               bool
+	DryRun               bool
+	OutDir               string
+	Header               string
 	enableMessageFlatten bool
+	Include              []string
+	Exclude              []string
+}
+
+// isTarget reports whether a descriptor name passes the include and exclude filters.
+func (o *ProtoOption) isTarget(name string) bool {
+	for _, pattern := range o.Exclude {
+		if matched, _ := path.Match(pattern, name); matched {
+			return false
+		}
+	}
+	if len(o.Include) == 0 {
+		return true
+	}
+	for _, pattern := range o.Include {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dataTypeKey identifies the options that affect parsed descriptors, so they can be shared.
+func (o *ProtoOption) dataTypeKey() string {
+	return fmt.Sprintf("%s,%s,%s,%t", o.Language, o.LongType, o.TypeMap, o.enableMessageFlatten)
 }
 
 func NewProtoOptionFromString(protoOption string) (*ProtoOption, error) {
-	templatePath, err := parseProtoOption(protoOption, "template")
+	params, err := parseParameter(protoOption)
+	if err != nil {
+		return nil, err
+	}
+
+	return newProtoOption(params)
+}
+
+func newProtoOption(params protoParameter) (*ProtoOption, error) {
+	templatePath, err := params.required("template")
+	if err != nil {
+		return nil, err
+	}
+	language, err := params.required("lang")
+	if err != nil {
+		return nil, err
+	}
+	outputDirectory, err := params.required("output_path")
+	if err != nil {
+		return nil, err
+	}
+	generateType, err := params.required("generate_type")
 	if err != nil {
 		return nil, err
 	}
-	language, err := parseProtoOption(protoOption, "lang")
+	allowMerge, err := params.boolean("allow_merge", false)
 	if err != nil {
 		return nil, err
 	}
-	outputDirectory, err := parseProtoOption(protoOption, "output_path")
+	overwrite, err := parseOverwriteMode(params.optional("overwrite"))
 	if err != nil {
 		return nil, err
 	}
-	generateType, err := parseProtoOption(protoOption, "generate_type")
+	longType := params.optional("long_type")
+	typeMap := params.optional("type_map")
+	enableMessageFlatten, err := params.boolean("enable_message_flatten", true)
 	if err != nil {
 		return nil, err
 	}
-	allowMerge := parseOptionalOption(protoOption, "allow_merge")
-	overwrite := parseOptionalOption(protoOption, "overwrite")
-	enableMessageFlatten := parseOptionalOption(protoOption, "enable_message_flatten")
+	strict, err := params.boolean("strict", false)
+	if err != nil {
+		return nil, err
+	}
+	dryRun, err := params.boolean("dry_run", false)
+	if err != nil {
+		return nil, err
+	}
+	outDir := params.optional("out_dir")
+	header, err := parseHeader(params.optional("header"), overwrite)
+	if err != nil {
+		return nil, err
+	}
+	include := params.list("include")
+	exclude := params.list("exclude")
+	for _, pattern := range append(include, exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid filter `%s`: %w", pattern, err)
+		}
+	}
 
 	return &ProtoOption{
 		TemplatePath:         templatePath,
 		Language:             language,
 		OutputPath:           outputDirectory,
 		GenerateType:         generateType,
-		AllowMerge:           allowMerge == "true",            // Default false
-		Overwrite:            overwrite != "false",            // Default true
-		enableMessageFlatten: enableMessageFlatten != "false", // Default true
+		AllowMerge:           allowMerge,
+		Overwrite:            overwrite,
+		LongType:             longType,
+		TypeMap:              typeMap,
+		Strict:               strict,
+		DryRun:               dryRun,
+		OutDir:               outDir,
+		Header:               header,
+		enableMessageFlatten: enableMessageFlatten,
+		Include:              include,
+		Exclude:              exclude,
 	}, nil
 }
-
-func parseProtoOption(optionString string, fieldName string) (string, error) {
-	spec := strings.Split(optionString, ",")
-	for _, p := range spec {
-		if strings.Contains(p, fieldName) {
-			return strings.Split(p, "=")[1], nil
-		}
-	}
-
-	return "", fmt.Errorf("option `%s` not found", fieldName)
-}
-
-func parseOptionalOption(optionString string, fieldName string) string {
-	spec := strings.Split(optionString, ",")
-	for _, p := range spec {
-		if strings.HasPrefix(p, fieldName) {
-			return strings.Split(p, "=")[1]
-		}
-	}
-
-	return ""
-}
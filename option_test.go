@@ -0,0 +1,171 @@
+package main_test
+
+import (
+	"testing"
+
+	templatefunc "github.com/deresmos/protoc-gen-template"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProtoOptionFromString(t *testing.T) {
+	tests := []struct {
+		name    string
+		param   string
+		want    *templatefunc.ProtoOption
+		wantErr string
+	}{
+		{
+			name:  "Required options",
+			param: "template=a.template,lang=typescript,generate_type=message,output_path=out.ts",
+			want: &templatefunc.ProtoOption{
+				TemplatePath: "a.template",
+				Language:     "typescript",
+				OutputPath:   "out.ts",
+				GenerateType: "message",
+				Overwrite:    templatefunc.OverwriteAlways,
+			},
+		},
+		{
+			name:  "Template in output path",
+			param: `template=a.template,lang=go,generate_type=message,output_path=./{{replace "." "/" .File.Package}}/{{printf "%s,%s" .MessageName "x"}}.go`,
+			want: &templatefunc.ProtoOption{
+				TemplatePath: "a.template",
+				Language:     "go",
+				OutputPath:   `./{{replace "." "/" .File.Package}}/{{printf "%s,%s" .MessageName "x"}}.go`,
+				GenerateType: "message",
+				Overwrite:    templatefunc.OverwriteAlways,
+			},
+		},
+		{
+			name:  "Quoted and escaped values",
+			param: `template="a,b=c.template",lang=dart,generate_type=service,output_path=out\,1.dart,long_type="say \"hi\""`,
+			want: &templatefunc.ProtoOption{
+				TemplatePath: "a,b=c.template",
+				Language:     "dart",
+				OutputPath:   "out,1.dart",
+				GenerateType: "service",
+				Overwrite:    templatefunc.OverwriteAlways,
+				LongType:     `say "hi"`,
+			},
+		},
+		{
+			name:  "Repeated keys from template_opt",
+			param: "template=a.template,lang=dart,generate_type=message,output_path=out.dart,include=*Request,include=*Response,lang=dart,overwrite=false",
+			want: &templatefunc.ProtoOption{
+				TemplatePath: "a.template",
+				Language:     "dart",
+				OutputPath:   "out.dart",
+				GenerateType: "message",
+				Overwrite:    templatefunc.OverwriteNever,
+				Include:      []string{"*Request", "*Response"},
+			},
+		},
+		{
+			name:  "Merge overwrite",
+			param: "template=a.template,lang=dart,generate_type=message,output_path=out.dart,overwrite=merge",
+			want: &templatefunc.ProtoOption{
+				TemplatePath: "a.template",
+				Language:     "dart",
+				OutputPath:   "out.dart",
+				GenerateType: "message",
+				Overwrite:    templatefunc.OverwriteMerge,
+			},
+		},
+		{
+			name:  "Output directory",
+			param: "template=a.template,lang=dart,generate_type=message,output_path=out.dart,overwrite=false,out_dir=gen",
+			want: &templatefunc.ProtoOption{
+				TemplatePath: "a.template",
+				Language:     "dart",
+				OutputPath:   "out.dart",
+				GenerateType: "message",
+				Overwrite:    templatefunc.OverwriteNever,
+				OutDir:       "gen",
+			},
+		},
+		{
+			name:  "Header with if_unmodified",
+			param: "template=a.template,lang=dart,generate_type=message,output_path=out.dart,overwrite=if_unmodified,header=true",
+			want: &templatefunc.ProtoOption{
+				TemplatePath: "a.template",
+				Language:     "dart",
+				OutputPath:   "out.dart",
+				GenerateType: "message",
+				Overwrite:    templatefunc.OverwriteIfUnmodified,
+				Header:       "true",
+			},
+		},
+		{
+			name:    "if_unmodified without header",
+			param:   "template=a.template,lang=dart,generate_type=message,output_path=out.dart,overwrite=if_unmodified",
+			wantErr: "overwrite `if_unmodified` requires the header option",
+		},
+		{
+			name:    "Invalid overwrite",
+			param:   "template=a.template,lang=dart,generate_type=message,output_path=out.dart,overwrite=yes",
+			wantErr: "invalid overwrite `yes`",
+		},
+		{
+			name:    "Capitalized bool",
+			param:   "template=a.template,lang=dart,generate_type=message,output_path=out.dart,dry_run=True",
+			wantErr: "option `dry_run` must be true or false, got `True`",
+		},
+		{
+			name:    "Bool as yes",
+			param:   "template=a.template,lang=dart,generate_type=message,output_path=out.dart,strict=yes",
+			wantErr: "option `strict` must be true or false, got `yes`",
+		},
+		{
+			name:    "Bool as number",
+			param:   "template=a.template,lang=dart,generate_type=message,output_path=out.dart,allow_merge=1",
+			wantErr: "option `allow_merge` must be true or false, got `1`",
+		},
+		{
+			name:    "Invalid enable_message_flatten",
+			param:   "template=a.template,lang=dart,generate_type=message,output_path=out.dart,enable_message_flatten=no",
+			wantErr: "option `enable_message_flatten` must be true or false, got `no`",
+		},
+		{
+			name:    "Prefix of another key",
+			param:   "template_dir=x,lang=dart,generate_type=message,output_path=out.dart",
+			wantErr: "unknown option `template_dir`",
+		},
+		{
+			name:    "Misspelled key",
+			param:   "template=a.template,langs=dart,generate_type=message,output_path=out.dart",
+			wantErr: "unknown option `langs`, did you mean `lang`?",
+		},
+		{
+			name:    "Conflicting duplicate key",
+			param:   "template=a.template,lang=dart,lang=go,generate_type=message,output_path=out.dart",
+			wantErr: "option `lang` given more than once",
+		},
+		{
+			name:    "Missing value",
+			param:   "template,lang=dart,generate_type=message,output_path=out.dart",
+			wantErr: "option `template` has no value",
+		},
+		{
+			name:    "Unterminated quote",
+			param:   `template="a.template,lang=dart`,
+			wantErr: "unterminated quote",
+		},
+		{
+			name:    "Missing required option",
+			param:   "template=a.template,lang=dart,generate_type=message",
+			wantErr: "option `output_path` not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := templatefunc.NewProtoOptionFromString(tt.param)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.EqualExportedValues(t, tt.want, got)
+		})
+	}
+}
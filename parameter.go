@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// knownOptions lists every key accepted in the plugin parameter.
+var knownOptions = []string{
+	"template",
+	"lang",
+	"output_path",
+	"generate_type",
+	"allow_merge",
+	"overwrite",
+	"enable_message_flatten",
+	"long_type",
+	"type_map",
+	"strict",
+	"dry_run",
+	"out_dir",
+	"header",
+	"include",
+	"exclude",
+	"config",
+}
+
+// listOptions may be given more than once, each occurrence adding a value.
+var listOptions = []string{
+	"include",
+	"exclude",
+}
+
+// protoParameter holds the values of a parsed plugin parameter by key.
+type protoParameter map[string][]string
+
+// parseParameter parses a comma separated list of key=value pairs.
+//
+// A value may be double quoted to contain commas, with \" and \\ as escapes.
+// Outside quotes, a comma is escaped as \, and commas inside {{ }} are kept,
+// so output path templates can be given as is.
+// protoc joins --template_opt values to the parameter with commas, so a key
+// may repeat: list options collect every value, and other options must repeat
+// the same value.
+func parseParameter(parameter string) (protoParameter, error) {
+	params := protoParameter{}
+	pairs, err := splitParameter(parameter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok {
+			return nil, fmt.Errorf("option `%s` has no value, expected %s=<value>", key, key)
+		}
+		if !slices.Contains(knownOptions, key) {
+			return nil, unknownOptionError(key)
+		}
+		value, err = unquoteValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("option `%s`: %w", key, err)
+		}
+
+		if values, ok := params[key]; ok && !slices.Contains(listOptions, key) {
+			if values[0] != value {
+				return nil, fmt.Errorf("option `%s` given more than once (%q and %q)", key, values[0], value)
+			}
+			continue
+		}
+		params[key] = append(params[key], value)
+	}
+
+	return params, nil
+}
+
+// splitParameter splits a parameter on the commas that separate options.
+func splitParameter(parameter string) ([]string, error) {
+	var pairs []string
+	var pair strings.Builder
+	inQuote := false
+	depth := 0
+	for i := 0; i < len(parameter); i++ {
+		c := parameter[i]
+		switch {
+		case c == '\\' && i+1 < len(parameter):
+			// Escapes are resolved by unquoteValue, except an unquoted \, which only matters here.
+			if !inQuote && parameter[i+1] == ',' {
+				pair.WriteByte(',')
+				i++
+				continue
+			}
+			pair.WriteByte(c)
+			i++
+			c = parameter[i]
+		case c == '"' && (inQuote || depth == 0 && strings.HasSuffix(pair.String(), "=")):
+			inQuote = !inQuote
+		case strings.HasPrefix(parameter[i:], "{{") && !inQuote:
+			depth++
+			pair.WriteString("{")
+			i++
+		case strings.HasPrefix(parameter[i:], "}}") && !inQuote && depth > 0:
+			depth--
+			pair.WriteString("}")
+			i++
+		case c == ',' && !inQuote && depth == 0:
+			pairs = append(pairs, pair.String())
+			pair.Reset()
+			continue
+		}
+		pair.WriteByte(c)
+	}
+	if inQuote {
+		return nil, fmt.Errorf("unterminated quote in parameter %q", parameter)
+	}
+	if pair.Len() > 0 {
+		pairs = append(pairs, pair.String())
+	}
+
+	return slices.DeleteFunc(pairs, func(pair string) bool {
+		return strings.TrimSpace(pair) == ""
+	}), nil
+}
+
+// unquoteValue strips the quotes of a quoted value and resolves its escapes.
+func unquoteValue(value string) (string, error) {
+	if !strings.HasPrefix(value, `"`) {
+		return value, nil
+	}
+	if len(value) < 2 || !strings.HasSuffix(value, `"`) {
+		return "", fmt.Errorf("unterminated quote in %s", value)
+	}
+
+	var unquoted strings.Builder
+	inner := value[1 : len(value)-1]
+	for i := 0; i < len(inner); i++ {
+		if inner[i] == '\\' && i+1 < len(inner) {
+			i++
+		} else if inner[i] == '"' {
+			return "", fmt.Errorf("unexpected quote in %s", value)
+		}
+		unquoted.WriteByte(inner[i])
+	}
+
+	return unquoted.String(), nil
+}
+
+func unknownOptionError(key string) error {
+	if suggestion := closestOption(key); suggestion != "" {
+		return fmt.Errorf("unknown option `%s`, did you mean `%s`?", key, suggestion)
+	}
+
+	return fmt.Errorf("unknown option `%s`, valid options are %s", key, strings.Join(knownOptions, ", "))
+}
+
+// closestOption returns the known option within two edits of key, if any.
+func closestOption(key string) string {
+	closest := ""
+	minDistance := 3
+	for _, option := range knownOptions {
+		if distance := editDistance(key, option); distance < minDistance {
+			closest = option
+			minDistance = distance
+		}
+	}
+
+	return closest
+}
+
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+
+	return previous[len(b)]
+}
+
+// required returns the value of key, or an error when it is missing.
+func (p protoParameter) required(key string) (string, error) {
+	values, ok := p[key]
+	if !ok {
+		return "", fmt.Errorf("option `%s` not found", key)
+	}
+
+	return values[0], nil
+}
+
+// optional returns the value of key, or an empty string when it is missing.
+func (p protoParameter) optional(key string) string {
+	if values, ok := p[key]; ok {
+		return values[0]
+	}
+
+	return ""
+}
+
+// boolean returns the value of a true/false option, or defaultValue when it is missing.
+func (p protoParameter) boolean(key string, defaultValue bool) (bool, error) {
+	switch value := p.optional(key); value {
+	case "":
+		return defaultValue, nil
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("option `%s` must be true or false, got `%s`", key, value)
+	}
+}
+
+// list returns every value given for key.
+func (p protoParameter) list(key string) []string {
+	return p[key]
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Protected regions are delimited by marker lines, in a comment of any language:
+//
+//	// protoc-gen-template:begin custom-methods
+//	...hand-written code kept across regenerations...
+//	// protoc-gen-template:end
+var (
+	regionBeginPattern = regexp.MustCompile(`protoc-gen-template:begin\s+([\w.-]+)`)
+	regionEndPattern   = regexp.MustCompile(`protoc-gen-template:end\b`)
+)
+
+// protectedRegions maps region names to their bodies, the lines between the markers.
+type protectedRegions map[string]string
+
+func parseProtectedRegions(content string) (protectedRegions, error) {
+	regions := protectedRegions{}
+	name := ""
+	var body strings.Builder
+	for i, line := range strings.SplitAfter(content, "\n") {
+		if match := regionBeginPattern.FindStringSubmatch(line); match != nil {
+			if name != "" {
+				return nil, fmt.Errorf("line %d: region `%s` begins inside region `%s`", i+1, match[1], name)
+			}
+			if _, ok := regions[match[1]]; ok {
+				return nil, fmt.Errorf("line %d: duplicate region `%s`", i+1, match[1])
+			}
+			name = match[1]
+			body.Reset()
+			continue
+		}
+		if regionEndPattern.MatchString(line) {
+			if name == "" {
+				return nil, fmt.Errorf("line %d: region end without begin", i+1)
+			}
+			regions[name] = body.String()
+			name = ""
+			continue
+		}
+		if name != "" {
+			body.WriteString(line)
+		}
+	}
+	if name != "" {
+		return nil, fmt.Errorf("region `%s` has no end", name)
+	}
+
+	return regions, nil
+}
+
+// spliceProtectedRegions replaces the bodies of the rendered regions with those of the existing file.
+// Every region of the existing file must still be rendered, so hand-written code is never dropped.
+func spliceProtectedRegions(rendered string, existing string) (string, error) {
+	renderedRegions, err := parseProtectedRegions(rendered)
+	if err != nil {
+		return "", fmt.Errorf("rendered: %w", err)
+	}
+	existingRegions, err := parseProtectedRegions(existing)
+	if err != nil {
+		return "", fmt.Errorf("existing file: %w", err)
+	}
+	if len(existingRegions) == 0 {
+		return rendered, nil
+	}
+	var missing []string
+	for name := range existingRegions {
+		if _, ok := renderedRegions[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return "", fmt.Errorf("region `%s` is no longer rendered by the template", strings.Join(missing, "`, `"))
+	}
+
+	var spliced strings.Builder
+	inRegion := false
+	for _, line := range strings.SplitAfter(rendered, "\n") {
+		if match := regionBeginPattern.FindStringSubmatch(line); match != nil {
+			spliced.WriteString(line)
+			if body, ok := existingRegions[match[1]]; ok {
+				spliced.WriteString(body)
+				inRegion = true
+			}
+			continue
+		}
+		if regionEndPattern.MatchString(line) {
+			inRegion = false
+		}
+		if !inRegion {
+			spliced.WriteString(line)
+		}
+	}
+
+	return spliced.String(), nil
+}
+
+// applyProtectedRegions keeps the protected regions of the files already on disk.
+func applyProtectedRegions(files []*generatedFile) error {
+	var errs []error
+	for _, file := range files {
+		if file.skipReason != "" {
+			continue
+		}
+		existing, err := os.ReadFile(file.destination())
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		content, err := spliceProtectedRegions(file.file.GetContent(), string(existing))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: template %s: %w", file.file.GetName(), file.descriptor, file.template, err))
+			continue
+		}
+		file.file.Content = &content
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	descriptor "google.golang.org/protobuf/types/descriptorpb"
+)
+
+// typeRegistry indexes every message and enum of a request by fully-qualified name,
+// so types declared in imported files or other packages can be resolved.
+type typeRegistry struct {
+	files map[string]*descriptor.FileDescriptorProto
+}
+
+func newTypeRegistry(files []*descriptor.FileDescriptorProto) *typeRegistry {
+	registry := &typeRegistry{
+		files: make(map[string]*descriptor.FileDescriptorProto),
+	}
+	for _, f := range files {
+		registry.registerMessages(f, f.GetPackage(), f.GetMessageType())
+		registry.registerEnums(f, f.GetPackage(), f.GetEnumType())
+	}
+
+	return registry
+}
+
+func (r *typeRegistry) registerMessages(f *descriptor.FileDescriptorProto, scope string, messageTypes []*descriptor.DescriptorProto) {
+	for _, messageType := range messageTypes {
+		fullName := qualifiedName(scope, messageType.GetName())
+		r.files[fullName] = f
+		r.registerMessages(f, fullName, messageType.GetNestedType())
+		r.registerEnums(f, fullName, messageType.GetEnumType())
+	}
+}
+
+func (r *typeRegistry) registerEnums(f *descriptor.FileDescriptorProto, scope string, enumTypes []*descriptor.EnumDescriptorProto) {
+	for _, enumType := range enumTypes {
+		r.files[qualifiedName(scope, enumType.GetName())] = f
+	}
+}
+
+// lookupFile returns the file declaring the type with the given fully-qualified name.
+func (r *typeRegistry) lookupFile(fullName string) (*descriptor.FileDescriptorProto, bool) {
+	f, ok := r.files[fullName]
+	return f, ok
+}
+
+func qualifiedName(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+
+	return scope + "." + name
+}
+
+type fileContext struct {
+	info      *FileInfo
+	locations sourceLocations
+}
+
+func newFileContext(f *descriptor.FileDescriptorProto) *fileContext {
+	return &fileContext{
+		info:      newFileInfo(f),
+		locations: newSourceLocations(f.GetSourceCodeInfo()),
+	}
+}
+
+func (c *fileContext) fullName(parents []MessageDescriptor, name string) string {
+	if len(parents) == 0 {
+		return qualifiedName(c.info.Package, name)
+	}
+
+	return qualifiedName(parents[len(parents)-1].FullName, name)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+
+	"google.golang.org/protobuf/proto"
+	plugin "google.golang.org/protobuf/types/pluginpb"
+)
+
+// manifestFileName is the only file written by dry_run=true.
+const manifestFileName = "protoc-gen-template.manifest.json"
+
+// generatedFile is a rendered file with where it came from, and why it is not written if skipped.
+type generatedFile struct {
+	file       *plugin.CodeGeneratorResponse_File
+	protoFile  string
+	descriptor string
+	template   string
+	overwrite  OverwriteMode
+	outDir     string
+	// checksum is set when the header rendered the checksum placeholder, stamped once the content is final.
+	checksum   bool
+	skipReason string
+}
+
+// destination returns where protoc writes the file, as response file names are relative to its output directory.
+func (f *generatedFile) destination() string {
+	return filepath.Join(f.outDir, f.file.GetName())
+}
+
+type generationManifest struct {
+	Files []manifestFile `json:"files"`
+}
+
+type manifestFile struct {
+	Path       string `json:"path"`
+	ProtoFile  string `json:"proto_file"`
+	Descriptor string `json:"descriptor"`
+	Template   string `json:"template"`
+	Size       int    `json:"size"`
+	Skipped    bool   `json:"skipped"`
+	SkipReason string `json:"skip_reason,omitempty"`
+}
+
+// writtenFiles returns the response files that were not skipped.
+func writtenFiles(files []*generatedFile) []*plugin.CodeGeneratorResponse_File {
+	var responseFiles []*plugin.CodeGeneratorResponse_File
+	for _, file := range files {
+		if file.skipReason == "" {
+			responseFiles = append(responseFiles, file.file)
+		}
+	}
+
+	return responseFiles
+}
+
+// manifestResponseFile reports every planned file as JSON instead of writing them.
+func manifestResponseFile(files []*generatedFile) (*plugin.CodeGeneratorResponse_File, error) {
+	manifest := generationManifest{
+		Files: []manifestFile{},
+	}
+	for _, file := range files {
+		manifest.Files = append(manifest.Files, manifestFile{
+			Path:       file.file.GetName(),
+			ProtoFile:  file.protoFile,
+			Descriptor: file.descriptor,
+			Template:   file.template,
+			Size:       len(file.file.GetContent()),
+			Skipped:    file.skipReason != "",
+			SkipReason: file.skipReason,
+		})
+	}
+
+	buf, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &plugin.CodeGeneratorResponse_File{
+		Name:    proto.String(manifestFileName),
+		Content: proto.String(string(buf) + "\n"),
+	}, nil
+}
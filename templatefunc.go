@@ -1,7 +1,6 @@
 package main
 
 import (
-	"os"
 	"strings"
 	"text/template"
 
@@ -59,16 +58,54 @@ func (t TemplateFunc) HasSuffix(s, suffix string) bool {
 	return strings.HasSuffix(s, suffix)
 }
 
-func initFileTemplate(file string) (*template.Template, error) {
-	var err error
-	var buf []byte
-	buf, err = os.ReadFile(file)
-	if err != nil {
-		return nil, err
+func commentLines(s string) []string {
+	s = strings.TrimRight(s, "\n")
+	if s == "" {
+		return nil
 	}
 
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(strings.TrimPrefix(line, " "), " \t")
+	}
+	return lines
+}
+
+// ToLineComment reflows a proto comment into lines starting with prefix, e.g. "//" or "///".
+func (t TemplateFunc) ToLineComment(prefix, s string) string {
+	lines := commentLines(s)
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = prefix
+			continue
+		}
+		lines[i] = prefix + " " + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ToBlockComment reflows a proto comment into a /** */ block indented by indent.
+func (t TemplateFunc) ToBlockComment(indent, s string) string {
+	lines := commentLines(s)
+	if len(lines) == 0 {
+		return ""
+	}
+
+	block := []string{indent + "/**"}
+	for _, line := range lines {
+		if line == "" {
+			block = append(block, indent+" *")
+			continue
+		}
+		block = append(block, indent+" * "+line)
+	}
+	block = append(block, indent+" */")
+	return strings.Join(block, "\n")
+}
+
+func initFileTemplate(file string, source string, strict bool) (*template.Template, error) {
 	templateFunc := NewTemplateFunc(pluralize.NewClient())
-	tmpl, err := template.New("gen-protoc").Funcs(template.FuncMap{
+	tmpl, err := template.New(file).Funcs(template.FuncMap{
 		"toCamelCase":      templateFunc.ToCamelCase,
 		"toKebab":          templateFunc.ToKebab,
 		"toLowerCamelCase": templateFunc.ToLowerCamelCase,
@@ -79,7 +116,9 @@ func initFileTemplate(file string) (*template.Template, error) {
 		"contains":         templateFunc.Contains,
 		"hasPrefix":        templateFunc.HasPrefix,
 		"hasSuffix":        templateFunc.HasSuffix,
-	}).Parse(string(buf))
+		"toLineComment":    templateFunc.ToLineComment,
+		"toBlockComment":   templateFunc.ToBlockComment,
+	}).Funcs((&fileDirectives{}).funcMap()).Option(missingKeyOption(strict)).Parse(source)
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +126,7 @@ func initFileTemplate(file string) (*template.Template, error) {
 	return tmpl, nil
 }
 
-func initOutputPathTemplate(outputPath string) (*template.Template, error) {
+func initOutputPathTemplate(outputPath string, strict bool) (*template.Template, error) {
 	templateFunc := NewTemplateFunc(pluralize.NewClient())
 	tmpl, err := template.New("gen-protoc-output-path").Funcs(template.FuncMap{
 		"toCamelCase":      templateFunc.ToCamelCase,
@@ -99,10 +138,19 @@ func initOutputPathTemplate(outputPath string) (*template.Template, error) {
 		"contains":         templateFunc.Contains,
 		"hasPrefix":        templateFunc.HasPrefix,
 		"hasSuffix":        templateFunc.HasSuffix,
-	}).Parse(outputPath)
+	}).Option(missingKeyOption(strict)).Parse(outputPath)
 	if err != nil {
 		return nil, err
 	}
 
 	return tmpl, nil
 }
+
+// missingKeyOption makes a missing map key an error in strict mode, instead of "<no value>".
+func missingKeyOption(strict bool) string {
+	if strict {
+		return "missingkey=error"
+	}
+
+	return "missingkey=default"
+}
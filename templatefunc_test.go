@@ -193,3 +193,77 @@ func TestContains(t *testing.T) {
 		})
 	}
 }
+
+func TestToLineComment(t *testing.T) {
+	tf := templatefunc.NewTemplateFunc(pluralize.NewClient())
+
+	tests := []struct {
+		name   string
+		prefix string
+		s      string
+		want   string
+	}{
+		{
+			name:   "Single line comment",
+			prefix: "//",
+			s:      " Hello world\n",
+			want:   "// Hello world",
+		},
+		{
+			name:   "Multi line comment with blank line",
+			prefix: "///",
+			s:      " Hello\n\n world\n",
+			want:   "/// Hello\n///\n/// world",
+		},
+		{
+			name:   "Empty comment",
+			prefix: "//",
+			s:      "",
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tf.ToLineComment(tt.prefix, tt.s)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestToBlockComment(t *testing.T) {
+	tf := templatefunc.NewTemplateFunc(pluralize.NewClient())
+
+	tests := []struct {
+		name   string
+		indent string
+		s      string
+		want   string
+	}{
+		{
+			name:   "Multi line comment",
+			indent: "",
+			s:      " Hello\n world\n",
+			want:   "/**\n * Hello\n * world\n */",
+		},
+		{
+			name:   "Indented comment",
+			indent: "  ",
+			s:      " Hello\n",
+			want:   "  /**\n   * Hello\n   */",
+		},
+		{
+			name:   "Empty comment",
+			indent: "",
+			s:      "",
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tf.ToBlockComment(tt.indent, tt.s)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}